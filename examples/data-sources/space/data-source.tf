@@ -0,0 +1,3 @@
+data "confluence_space" "example" {
+  key = "EXAMPLE"
+}
@@ -0,0 +1,5 @@
+data "confluence_spaces" "example" {
+  type   = "global"
+  status = "current"
+  label  = "team"
+}
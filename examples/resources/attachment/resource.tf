@@ -0,0 +1,6 @@
+resource "confluence_attachment" "diagram" {
+  page_id = confluence_content.runbook.id
+  title   = "architecture.png"
+  source  = "${path.module}/architecture.png"
+  comment = "Updated by Terraform"
+}
\ No newline at end of file
@@ -0,0 +1,10 @@
+resource "confluence_content" "runbook" {
+  space  = "TST"
+  title  = "Runbook"
+  parent = "123456"
+  labels = ["runbook", "oncall"]
+  body   = <<EOT
+<h1>Runbook</h1>
+<p>Steps to follow when the pager goes off.</p>
+EOT
+}
\ No newline at end of file
@@ -0,0 +1,4 @@
+resource "confluence_group_members" "test" {
+  group_id    = "group-id"
+  account_ids = ["accountIdA", "accountIdB"]
+}
\ No newline at end of file
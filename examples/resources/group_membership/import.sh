@@ -0,0 +1,2 @@
+# Group memberships are imported by group id and account id
+terraform import confluence_group_membership.example 3fa85f64-5717-4562-b3fc-2c963f66afa6/5b10a2844c20165700ede21g
@@ -1,4 +1,5 @@
 resource "confluence_space" "test_space" {
-  key  = "TST"
-  name = "Test Space"
+  key         = "TST"
+  name        = "Test Space"
+  description = "Space managed by terraform"
 }
\ No newline at end of file
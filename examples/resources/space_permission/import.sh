@@ -0,0 +1,4 @@
+# Space permissions are imported by space key and subject
+terraform import confluence_space_permission.group EXAMPLE/group/confluence-users
+terraform import confluence_space_permission.user EXAMPLE/user/5b10a2844c20165700ede21g
+terraform import confluence_space_permission.anonymous EXAMPLE/anonymous
@@ -5,4 +5,20 @@ resource "confluence_space_permission" "test_space_permissions" {
     "read:space",
   ]
   group = "group_name"
+}
+
+resource "confluence_space_permission" "guest_permissions" {
+  key = "TST"
+  operations = [
+    "read:space",
+  ]
+  user = "5b10ac8d82e05b22cc7d4ef5"
+}
+
+resource "confluence_space_permission" "anonymous_permissions" {
+  key = "TST"
+  operations = [
+    "read:space",
+  ]
+  anonymous = true
 }
\ No newline at end of file
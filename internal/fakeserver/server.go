@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -25,6 +26,15 @@ type Fakeserver struct {
 	running     bool
 	handler     func(a string, b []byte) (string, map[string]interface{})
 	handlerPath string
+	faultsMutex sync.Mutex
+	faults      []injectedResponse
+	requests    int
+}
+
+/*injectedResponse is a canned response returned instead of processing a request*/
+type injectedResponse struct {
+	status  int
+	headers map[string]string
 }
 
 /*NewFakeServer creates a HTTP server used for tests and debugging*/
@@ -101,11 +111,51 @@ func (svr *Fakeserver) SetSplice(pathForHandler string, handler func(a string, b
 	return svr.server
 }
 
+/*InjectResponses makes the next count requests fail with the given status code and headers*/
+func (svr *Fakeserver) InjectResponses(count int, status int, headers map[string]string) {
+	svr.faultsMutex.Lock()
+	defer svr.faultsMutex.Unlock()
+	for i := 0; i < count; i++ {
+		svr.faults = append(svr.faults, injectedResponse{status: status, headers: headers})
+	}
+}
+
+/*RequestCount returns the number of requests received so far, including the failed ones*/
+func (svr *Fakeserver) RequestCount() int {
+	svr.faultsMutex.Lock()
+	defer svr.faultsMutex.Unlock()
+	return svr.requests
+}
+
+/*nextFault records the request and pops the next injected response, if any*/
+func (svr *Fakeserver) nextFault() *injectedResponse {
+	svr.faultsMutex.Lock()
+	defer svr.faultsMutex.Unlock()
+	svr.requests++
+	if len(svr.faults) == 0 {
+		return nil
+	}
+	fault := svr.faults[0]
+	svr.faults = svr.faults[1:]
+	return &fault
+}
+
 func (svr *Fakeserver) handleAPIObject(w http.ResponseWriter, r *http.Request) {
 	var obj map[string]interface{}
 	var id string
 	var ok bool
 
+	if fault := svr.nextFault(); fault != nil {
+		if svr.debug {
+			log.Printf("fakeserver.go: Injecting %d response for %s %s\n", fault.status, r.Method, r.URL.Path)
+		}
+		for name, value := range fault.headers {
+			w.Header().Set(name, value)
+		}
+		http.Error(w, http.StatusText(fault.status), fault.status)
+		return
+	}
+
 	/* Assume this will never fail */
 	b, _ := ioutil.ReadAll(r.Body)
 
@@ -0,0 +1,38 @@
+package helpers
+
+import "fmt"
+
+// APIVersion selects the Confluence REST API a resource talks to
+type APIVersion string
+
+// Supported REST API versions
+const (
+	// APIVersionV1 is served under /rest/api by Cloud and Data Center
+	APIVersionV1 APIVersion = "v1"
+	// APIVersionV2 is served under /api/v2 by Confluence Cloud only
+	APIVersionV2 APIVersion = "v2"
+)
+
+// APIVersions lists every supported REST API version
+var APIVersions = []string{string(APIVersionV1), string(APIVersionV2)}
+
+// ParseAPIVersion validates a configured API version, an empty string selects it automatically
+func ParseAPIVersion(version string) (APIVersion, error) {
+	switch APIVersion(version) {
+	case "", APIVersionV1, APIVersionV2:
+		return APIVersion(version), nil
+	}
+	return "", fmt.Errorf("unsupported API version %q, expected one of %v", version, APIVersions)
+}
+
+// APIVersion returns the REST API version resources should read through. Unless it is configured, Cloud
+// uses v2 and Data Center, which has no v2 API, uses v1.
+func (c *Client) APIVersion() APIVersion {
+	if c.apiVersion != "" {
+		return c.apiVersion
+	}
+	if c.IsCloud() {
+		return APIVersionV2
+	}
+	return APIVersionV1
+}
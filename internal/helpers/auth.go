@@ -0,0 +1,91 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Supported authentication types
+const (
+	AuthTypeBasic  = "basic"
+	AuthTypeBearer = "bearer"
+	AuthTypeOAuth  = "oauth"
+	// AuthTypeClientCredentials fetches OAuth 2.0 access tokens for service accounts
+	AuthTypeClientCredentials = "client_credentials"
+)
+
+// AuthTypes lists every supported authentication type
+var AuthTypes = []string{AuthTypeBasic, AuthTypeBearer, AuthTypeOAuth, AuthTypeClientCredentials}
+
+// UsesCloudGateway reports whether requests authenticated with the given type have to be routed through
+// the API gateway when talking to Confluence Cloud
+func UsesCloudGateway(authType string) bool {
+	return authType == AuthTypeOAuth || authType == AuthTypeClientCredentials
+}
+
+// Authenticator adds the credentials to every request sent to Confluence.
+// Credentials only ever travel in headers, so they never end up in URLs or error messages.
+type Authenticator interface {
+	Authenticate(ctx context.Context, req *http.Request) error
+}
+
+// Invalidator is implemented by credentials that are cached and can be fetched again. Invalidate drops the
+// cached credentials after Confluence rejected them, so the next request authenticates with fresh ones.
+type Invalidator interface {
+	Invalidate()
+}
+
+// BasicAuth authenticates with a username and an API token or password
+func BasicAuth(username string, password string) Authenticator {
+	return basicAuth{username: username, password: password}
+}
+
+type basicAuth struct {
+	username string
+	password string
+}
+
+func (a basicAuth) Authenticate(_ context.Context, req *http.Request) error {
+	if a.username == "" && a.password == "" {
+		return nil
+	}
+	req.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+// String keeps the password out of logs and error messages
+func (a basicAuth) String() string {
+	return fmt.Sprintf("basic auth for %q", a.username)
+}
+
+// BearerToken authenticates with a Data Center personal access token or an OAuth 2.0 access token
+func BearerToken(token string) Authenticator {
+	return bearerToken{token: token}
+}
+
+type bearerToken struct {
+	token string
+}
+
+func (a bearerToken) Authenticate(_ context.Context, req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+// String keeps the token out of logs and error messages
+func (a bearerToken) String() string {
+	return "bearer token"
+}
+
+// NewAuthenticator returns the authenticator for the given authentication type
+func NewAuthenticator(authType string, username string, token string) (Authenticator, error) {
+	switch authType {
+	case "", AuthTypeBasic:
+		return BasicAuth(username, token), nil
+	case AuthTypeBearer, AuthTypeOAuth:
+		return BearerToken(token), nil
+	default:
+		return nil, fmt.Errorf("unsupported authentication type %q", authType)
+	}
+}
@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthenticators(t *testing.T) {
+	cases := map[string]struct {
+		authType string
+		expected string
+	}{
+		"basic":   {authType: AuthTypeBasic, expected: "Basic dXNlcjpzM2NyM3Q="},
+		"default": {authType: "", expected: "Basic dXNlcjpzM2NyM3Q="},
+		"bearer":  {authType: AuthTypeBearer, expected: "Bearer s3cr3t"},
+		"oauth":   {authType: AuthTypeOAuth, expected: "Bearer s3cr3t"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var header string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header.Get("Authorization")
+				w.Write([]byte(`{}`))
+			}))
+			defer server.Close()
+
+			auth, err := NewAuthenticator(c.authType, "user", "s3cr3t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			client := NewClient(&NewClientInput{Site: strings.TrimPrefix(server.URL, "http://"), Auth: auth})
+			if err := client.Get(context.Background(), "/rest/api/space", nil); err != nil {
+				t.Fatal(err)
+			}
+			if header != c.expected {
+				t.Fatalf("expected Authorization %q, got %q", c.expected, header)
+			}
+			if strings.Contains(fmt.Sprint(auth), "s3cr3t") {
+				t.Fatalf("expected the authenticator to hide the secret, got %s", auth)
+			}
+		})
+	}
+
+	if _, err := NewAuthenticator("kerberos", "user", "s3cr3t"); err == nil {
+		t.Fatal("expected an error for an unsupported authentication type")
+	}
+}
+
+func TestClientErrorsDoNotLeakCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"statusCode":401,"message":"Unauthorized"}`))
+	}))
+	site := strings.TrimPrefix(server.URL, "http://")
+
+	for _, authType := range AuthTypes {
+		auth, _ := NewAuthenticator(authType, "jdoe", "s3cr3t")
+		client := NewClient(&NewClientInput{Site: site, Auth: auth, MaxRetries: 0})
+
+		err := client.Get(context.Background(), "/rest/api/space", nil)
+		if err == nil || strings.Contains(err.Error(), "s3cr3t") {
+			t.Fatalf("%s: expected an error without credentials, got: %v", authType, err)
+		}
+	}
+
+	// Transport errors include the URL of the request
+	server.Close()
+	client := NewClient(&NewClientInput{Site: site, Username: "jdoe", Password: "s3cr3t", MaxRetries: 0})
+	err := client.Get(context.Background(), "/rest/api/space", nil)
+	if err == nil || strings.Contains(err.Error(), "s3cr3t") || strings.Contains(err.Error(), "jdoe") {
+		t.Fatalf("expected a transport error without credentials, got: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported deployment types
+const (
+	DeploymentTypeCloud      = "cloud"
+	DeploymentTypeDataCenter = "datacenter"
+)
+
+// DeploymentTypes lists every supported deployment type
+var DeploymentTypes = []string{DeploymentTypeCloud, DeploymentTypeDataCenter}
+
+// Feature is part of the API that only some deployments offer
+type Feature string
+
+// Features that depend on the deployment
+const (
+	FeatureAPIv2 Feature = "the REST API v2"
+	// FeatureGroupIds covers the group endpoints that address groups by id instead of by name
+	FeatureGroupIds Feature = "groups addressed by id"
+)
+
+// Capabilities describes the Confluence deployment the client talks to
+type Capabilities struct {
+	DeploymentType string
+	// Version and BuildNumber are empty when the deployment could not be probed
+	Version     string
+	BuildNumber int
+	// Detected is false when the deployment could not be probed
+	Detected bool
+	// Configured is true when the deployment type was set in the provider configuration
+	Configured bool
+}
+
+// IsCloud reports whether the deployment is Confluence Cloud
+func (c Capabilities) IsCloud() bool {
+	return c.DeploymentType == DeploymentTypeCloud
+}
+
+// Guessed reports whether the deployment type was only derived from the host name
+func (c Capabilities) Guessed() bool {
+	return !c.Detected && !c.Configured
+}
+
+// Supports reports whether the deployment offers the feature
+func (c Capabilities) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureAPIv2, FeatureGroupIds:
+		return c.IsCloud()
+	}
+	return true
+}
+
+// String names the deployment for diagnostics, e.g. Confluence Data Center 8.5.4 (build 9012)
+func (c Capabilities) String() string {
+	name := "Confluence Data Center"
+	if c.IsCloud() {
+		name = "Confluence Cloud"
+	}
+	if c.Version == "" || c.IsCloud() {
+		return name
+	}
+	return fmt.Sprintf("%s %s (build %d)", name, c.Version, c.BuildNumber)
+}
+
+// guessDeploymentType derives the deployment type from the host name, Cloud sites may use custom domains
+func guessDeploymentType(host string) string {
+	if strings.HasSuffix(host, ".atlassian.net") || host == CloudGatewayHost {
+		return DeploymentTypeCloud
+	}
+	return DeploymentTypeDataCenter
+}
+
+// applicationManifest is returned by the application links plugin, which is bundled with every deployment
+type applicationManifest struct {
+	TypeId      string `json:"typeId"`
+	Version     string `json:"version"`
+	BuildNumber int    `json:"buildNumber"`
+}
+
+// cloudMajorVersion is reported by every Confluence Cloud site
+const cloudMajorVersion = 1000
+
+// Capabilities returns the deployment the client talks to
+func (c *Client) Capabilities() Capabilities {
+	return c.capabilities
+}
+
+// capabilityProbeTimeout bounds the probe, an unreachable server should not hold up the provider
+const capabilityProbeTimeout = 5 * time.Second
+
+// DetectCapabilities probes the server once for its deployment type, version and build number. The probe
+// uses the configured path context, is not retried and the client keeps the guessed deployment type
+// when it fails.
+func (c *Client) DetectCapabilities(ctx context.Context) error {
+	if c.baseURL.Host == CloudGatewayHost {
+		// The gateway only routes Confluence Cloud, which it does not expose the manifest for
+		c.capabilities.Detected = true
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(withoutRetries(ctx), capabilityProbeTimeout)
+	defer cancel()
+	var manifest applicationManifest
+	if err := c.Get(ctx, "/rest/applinks/1.0/manifest", &manifest); err != nil {
+		return err
+	}
+	if manifest.TypeId != "confluence" {
+		return fmt.Errorf("the server identifies as %q instead of confluence", manifest.TypeId)
+	}
+
+	deploymentType := DeploymentTypeDataCenter
+	major, _, _ := strings.Cut(manifest.Version, ".")
+	if version, err := strconv.Atoi(major); err == nil && version >= cloudMajorVersion {
+		deploymentType = DeploymentTypeCloud
+	}
+	if !c.capabilities.Configured {
+		c.capabilities.DeploymentType = deploymentType
+	}
+	c.capabilities.Version = manifest.Version
+	c.capabilities.BuildNumber = manifest.BuildNumber
+	c.capabilities.Detected = true
+	return nil
+}
@@ -0,0 +1,111 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func newManifestTestClient(t *testing.T, manifests map[string]string, input NewClientInput) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		manifest, ok := manifests[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(manifest))
+	}))
+	t.Cleanup(server.Close)
+	serverURL, _ := url.Parse(server.URL)
+	input.Site = serverURL.Host
+	return NewClient(&input)
+}
+
+func TestDetectCapabilities(t *testing.T) {
+	dataCenter := `{"typeId": "confluence", "version": "8.5.4", "buildNumber": 9012}`
+	cloud := `{"typeId": "confluence", "version": "1000.0.0-5e1d45a0b6f2", "buildNumber": 6452}`
+	jira := `{"typeId": "jira", "version": "1001.0.0-SNAPSHOT", "buildNumber": 100233}`
+
+	t.Run("data center", func(t *testing.T) {
+		client := newManifestTestClient(t, map[string]string{"/confluence/rest/applinks/1.0/manifest": dataCenter}, NewClientInput{Context: "/confluence"})
+		if err := client.DetectCapabilities(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		capabilities := client.Capabilities()
+		if capabilities.IsCloud() || !capabilities.Detected || capabilities.Version != "8.5.4" || capabilities.BuildNumber != 9012 {
+			t.Fatalf("unexpected capabilities: %+v", capabilities)
+		}
+		if capabilities.Supports(FeatureGroupIds) || client.APIVersion() != APIVersionV1 {
+			t.Fatal("expected Data Center to lack the Cloud features")
+		}
+		if capabilities.String() != "Confluence Data Center 8.5.4 (build 9012)" {
+			t.Fatalf("unexpected name: %s", capabilities)
+		}
+	})
+
+	t.Run("cloud below the configured context", func(t *testing.T) {
+		client := newManifestTestClient(t, map[string]string{
+			"/rest/applinks/1.0/manifest":      jira,
+			"/wiki/rest/applinks/1.0/manifest": cloud,
+		}, NewClientInput{Context: "/wiki"})
+		if err := client.DetectCapabilities(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if !client.IsCloud() || client.APIVersion() != APIVersionV2 {
+			t.Fatalf("expected Cloud, got %+v", client.Capabilities())
+		}
+	})
+
+	t.Run("context is not guessed", func(t *testing.T) {
+		client := newManifestTestClient(t, map[string]string{
+			"/rest/applinks/1.0/manifest":      jira,
+			"/wiki/rest/applinks/1.0/manifest": cloud,
+		}, NewClientInput{})
+		if err := client.DetectCapabilities(context.Background()); err == nil {
+			t.Fatal("expected an error for a server that is not Confluence")
+		}
+		if client.basePath != "" || client.Capabilities().Detected {
+			t.Fatalf("expected the configured context to be kept, got %+v at %q", client.Capabilities(), client.basePath)
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		client := newManifestTestClient(t, map[string]string{"/rest/applinks/1.0/manifest": dataCenter}, NewClientInput{DeploymentType: DeploymentTypeCloud})
+		if err := client.DetectCapabilities(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if !client.IsCloud() || client.Capabilities().Version != "8.5.4" {
+			t.Fatalf("expected the configured deployment type to win, got %+v", client.Capabilities())
+		}
+	})
+
+	t.Run("probe fails", func(t *testing.T) {
+		client := newManifestTestClient(t, map[string]string{}, NewClientInput{})
+		if err := client.DetectCapabilities(context.Background()); err == nil {
+			t.Fatal("expected an error when the manifest is missing")
+		}
+		if client.IsCloud() || client.Capabilities().Detected || client.basePath != "" {
+			t.Fatalf("expected the guessed deployment type and the original context, got %+v at %q", client.Capabilities(), client.basePath)
+		}
+	})
+}
+
+func TestDetectCapabilitiesProbesOnce(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+
+	client := NewClient(&NewClientInput{Site: serverURL.Host, MaxRetries: 3})
+	if err := client.DetectCapabilities(context.Background()); err == nil {
+		t.Fatal("expected an error when the server is unavailable")
+	}
+	if requests != 1 {
+		t.Fatalf("expected the probe not to be retried, got %d requests", requests)
+	}
+}
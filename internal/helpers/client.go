@@ -2,19 +2,31 @@ package helpers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Client provides a connection to the Confluence API
 type Client struct {
-	client    *http.Client
-	baseURL   *url.URL
-	basePath  string
-	publicURL *url.URL
+	client     *http.Client
+	baseURL    *url.URL
+	basePath   string
+	context    string
+	publicURL  *url.URL
+	retry      *retryPolicy
+	auth       Authenticator
+	debug      bool
+	apiVersion APIVersion
+
+	capabilities Capabilities
 }
 
 // NewClientInput provides information to connect to the Confluence API
@@ -26,12 +38,22 @@ type NewClientInput struct {
 	Context          string
 	Username         string
 	Password         string
-}
-
-// ErrorResponse describes why a request failed
-type ErrorResponse struct {
-	StatusCode int    `json:"status_code,omitempty"`
-	Message    string `json:"message,omitempty"`
+	// Auth overrides the basic authentication with Username and Password
+	Auth Authenticator
+	// CloudId routes all requests through the API gateway of Confluence Cloud
+	CloudId      string
+	MaxRetries   int
+	MaxRetryWait time.Duration
+	// Debug adds the scrubbed request body to the errors of failed requests
+	Debug bool
+	// Transport carries the TLS and proxy settings, see NewTransport
+	Transport http.RoundTripper
+	// Timeout limits every single request, including reading the response body
+	Timeout time.Duration
+	// APIVersion overrides the REST API version resources read through, see Client.APIVersion
+	APIVersion APIVersion
+	// DeploymentType overrides the detected deployment type, see Client.DetectCapabilities
+	DeploymentType string
 }
 
 // NewClient returns an authenticated client ready to use
@@ -48,21 +70,49 @@ func NewClient(input *NewClientInput) *Client {
 		Scheme: ifThenElse(input.SiteUseTLS, "https", "http").(string),
 		Host:   input.Site,
 	}
-	baseURL.User = url.UserPassword(input.Username, input.Password)
+	if input.CloudId != "" {
+		baseURL = url.URL{Scheme: "https", Host: CloudGatewayHost}
+		basePath = "/ex/confluence/" + input.CloudId + basePath
+	}
+
+	auth := input.Auth
+	if auth == nil {
+		auth = BasicAuth(input.Username, input.Password)
+	}
+	transport := input.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	timeout := input.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	capabilities := Capabilities{DeploymentType: input.DeploymentType, Configured: input.DeploymentType != ""}
+	if capabilities.DeploymentType == "" {
+		capabilities.DeploymentType = guessDeploymentType(baseURL.Hostname())
+	}
 	return &Client{
 		client: &http.Client{
-			Timeout: time.Second * 10,
+			Transport: newLoggingTransport(transport),
+			Timeout:   timeout,
 		},
-		baseURL:   &baseURL,
-		basePath:  basePath,
-		publicURL: &publicURL,
+		baseURL:    &baseURL,
+		basePath:   basePath,
+		context:    input.Context,
+		publicURL:  &publicURL,
+		retry:      newRetryPolicy(input.MaxRetries, input.MaxRetryWait),
+		auth:       auth,
+		debug:      input.Debug,
+		apiVersion: input.APIVersion,
+
+		capabilities: capabilities,
 	}
 }
 
 // GetString uses the client to send a GET request and returns a string
-func (c *Client) GetString(path string) (string, error) {
+func (c *Client) GetString(ctx context.Context, path string) (string, error) {
 	body := new(bytes.Buffer)
-	responseBody, err := c.doRaw("GET", path, "", body)
+	responseBody, err := c.doRaw(ctx, "GET", path, "", body)
 	if err != nil {
 		return "", err
 	}
@@ -71,19 +121,19 @@ func (c *Client) GetString(path string) (string, error) {
 }
 
 // Get uses the client to send a GET request
-func (c *Client) Get(path string, result interface{}) error {
+func (c *Client) Get(ctx context.Context, path string, result interface{}) error {
 	body := new(bytes.Buffer)
-	return c.do("GET", path, "", body, result)
+	return c.do(ctx, "GET", path, "", body, result)
 }
 
 // Delete uses the client to send a DELETE request
-func (c *Client) Delete(path string) error {
+func (c *Client) Delete(ctx context.Context, path string) error {
 	body := new(bytes.Buffer)
-	return c.do("DELETE", path, "", body, nil)
+	return c.do(ctx, "DELETE", path, "", body, nil)
 }
 
 // Post uses the client to send a POST request
-func (c *Client) Post(path string, body interface{}, result interface{}, itemsToRemove []string) error {
+func (c *Client) Post(ctx context.Context, path string, body interface{}, result interface{}, itemsToRemove []string) error {
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
 		return err
@@ -95,11 +145,11 @@ func (c *Client) Post(path string, body interface{}, result interface{}, itemsTo
 	if err != nil {
 		return err
 	}
-	return c.do("POST", path, "application/json", b, result)
+	return c.do(ctx, "POST", path, "application/json", b, result)
 }
 
 // Put uses the client to send a PUT request
-func (c *Client) Put(path string, body interface{}, result interface{}, itemsToRemove []string) error {
+func (c *Client) Put(ctx context.Context, path string, body interface{}, result interface{}, itemsToRemove []string) error {
 	bodyBytes, err := json.Marshal(body)
 	if err != nil {
 		return err
@@ -111,7 +161,29 @@ func (c *Client) Put(path string, body interface{}, result interface{}, itemsToR
 	if err != nil {
 		return err
 	}
-	return c.do("PUT", path, "application/json", b, result)
+	return c.do(ctx, "PUT", path, "application/json", b, result)
+}
+
+// PostForm uses the client to upload a file as multipart/form-data, additional form fields are sent before the file
+func (c *Client) PostForm(ctx context.Context, path string, filename string, data []byte, fields map[string]string, result interface{}) error {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return c.do(ctx, "POST", path, writer.FormDataContentType(), body, result)
 }
 
 func JsonBytesBuffer(body interface{}) (*bytes.Buffer, error) {
@@ -134,8 +206,8 @@ func bytesBufferJSON(bodyBytes *bytes.Buffer, result interface{}) error {
 	return json.NewDecoder(reader).Decode(&result)
 }
 
-func (c *Client) do(method, path, contentType string, body *bytes.Buffer, result interface{}) error {
-	responseBody, err := c.doRaw(method, path, contentType, body)
+func (c *Client) do(ctx context.Context, method, path, contentType string, body *bytes.Buffer, result interface{}) error {
+	responseBody, err := c.doRaw(ctx, method, path, contentType, body)
 	if err != nil {
 		return err
 	}
@@ -143,23 +215,65 @@ func (c *Client) do(method, path, contentType string, body *bytes.Buffer, result
 }
 
 // do use the client to send a specified request
-func (c *Client) doRaw(method, path, contentType string, body *bytes.Buffer) (*bytes.Buffer, error) {
+func (c *Client) doRaw(ctx context.Context, method, path, contentType string, body *bytes.Buffer) (*bytes.Buffer, error) {
 	fullPath := c.basePath + path
 	u, err := c.baseURL.Parse(fullPath)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest(method, u.String(), body)
-	if err != nil {
-		return nil, err
-	}
-	if contentType != "" {
-		req.Header.Set("Content-Type", contentType)
+	ctx = tflog.SetField(ctx, "confluence_method", method)
+	ctx = tflog.SetField(ctx, "confluence_path", RedactPath(fullPath))
+	// Keep the payload around so every attempt sends the complete body
+	var payload []byte
+	if body != nil {
+		payload = body.Bytes()
 	}
-	req.Header.Add("X-Atlassian-Token", "nocheck")
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, err
+	var resp *http.Response
+	reauthenticated := false
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(withAttempt(ctx, attempt), method, u.String(), bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Add("X-Atlassian-Token", "nocheck")
+		if err := c.auth.Authenticate(ctx, req); err != nil {
+			return nil, err
+		}
+		resp, err = c.client.Do(req)
+		if invalidator, ok := c.auth.(Invalidator); ok && err == nil && resp.StatusCode == http.StatusUnauthorized && !reauthenticated {
+			// The cached credentials may have been revoked, authenticate again once
+			reauthenticated = true
+			invalidator.Invalidate()
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			tflog.Debug(ctx, "Confluence rejected the credentials, authenticating again")
+			attempt--
+			continue
+		}
+		if retriesDisabled(ctx) || !c.retry.shouldRetry(method, resp, err, attempt) {
+			if urlError, ok := err.(*url.Error); ok {
+				// Transport errors repeat the URL of the request
+				urlError.URL = RedactPath(urlError.URL)
+			}
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+		wait := c.retry.delay(attempt, resp)
+		if resp != nil {
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		tflog.Debug(ctx, "Retrying request to Confluence", map[string]interface{}{"confluence_attempt": attempt, "confluence_wait": wait.String()})
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
 	}
 	defer resp.Body.Close()
 	var expectedStatusCode = map[string][]int{
@@ -169,17 +283,20 @@ func (c *Client) doRaw(method, path, contentType string, body *bytes.Buffer) (*b
 		"DELETE": {200, 202, 204},
 	}
 	if !Contains(expectedStatusCode[method], resp.StatusCode) {
-		var responseBody string
+		apiError := &APIError{
+			Method:     method,
+			Path:       RedactPath(fullPath),
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+		if c.debug {
+			apiError.RequestBody = RedactBody(payload)
+		}
 		var errResponse ErrorResponse
-		err = json.NewDecoder(resp.Body).Decode(&errResponse)
-		if err != nil {
-			responseBody = "Could not decode error"
-		} else {
-			responseBody = errResponse.String()
+		if err := json.NewDecoder(resp.Body).Decode(&errResponse); err == nil {
+			apiError.Response = &errResponse
 		}
-		s := body.String()
-		return nil, fmt.Errorf("%s\n\n%s %s\n%s\n\n%s",
-			resp.Status, method, fullPath, s, responseBody)
+		return nil, apiError
 	}
 	result := new(bytes.Buffer)
 	_, err = result.ReadFrom(resp.Body)
@@ -189,9 +306,16 @@ func (c *Client) doRaw(method, path, contentType string, body *bytes.Buffer) (*b
 	return result, nil
 }
 
-func (e *ErrorResponse) String() string {
-	return fmt.Sprintf("%s\nCode: %d",
-		e.Message, e.StatusCode)
+// sleepContext waits for the given duration unless the context is cancelled first
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
 }
 
 // URL returns the public URL for a given path
@@ -202,3 +326,28 @@ func (c *Client) URL(path string) string {
 	}
 	return u.String()
 }
+
+// RelativePath turns a link returned by the API into a path for the client. Links may be absolute and may
+// include the path context, e.g. the next links of the v2 API.
+func (c *Client) RelativePath(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	path := u.EscapedPath()
+	for _, prefix := range []string{c.basePath, c.context} {
+		if prefix != "" && strings.HasPrefix(path, prefix+"/") {
+			path = strings.TrimPrefix(path, prefix)
+			break
+		}
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return path
+}
+
+// IsCloud reports whether the client talks to Confluence Cloud rather than a self-hosted instance
+func (c *Client) IsCloud() bool {
+	return c.capabilities.IsCloud()
+}
@@ -0,0 +1,270 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"terraform-provider-confluence/internal/fakeserver"
+)
+
+const (
+	testClientHost = "127.0.0.1"
+	testClientPort = 9998
+)
+
+func newTestClient(maxRetries int) *Client {
+	client := NewClient(&NewClientInput{
+		Site:         fmt.Sprintf("%s:%d", testClientHost, testClientPort),
+		SiteUseTLS:   false,
+		Username:     "test123",
+		Password:     "test123",
+		MaxRetries:   maxRetries,
+		MaxRetryWait: 50 * time.Millisecond,
+	})
+	client.retry.minWait = time.Millisecond
+	return client
+}
+
+func newTestServer(objects map[string]map[string]interface{}) *fakeserver.Fakeserver {
+	svr := fakeserver.NewFakeServer(testClientPort, objects, true, false, "")
+	svr.SetSplice("/rest/api/space/", func(path string, b []byte) (string, map[string]interface{}) {
+		id := path[strings.LastIndex(path, "/")+1:]
+		if obj, ok := objects[id]; ok {
+			return id, obj
+		}
+		return id, map[string]interface{}{}
+	})
+	return svr
+}
+
+func TestClientRetries(t *testing.T) {
+	objects := map[string]map[string]interface{}{
+		"KEY": {"key": "KEY", "name": "Space"},
+	}
+	svr := newTestServer(objects)
+	defer svr.Shutdown()
+
+	t.Run("rate limited", func(t *testing.T) {
+		start := svr.RequestCount()
+		svr.InjectResponses(2, http.StatusTooManyRequests, map[string]string{"Retry-After": "0"})
+		var result map[string]interface{}
+		if err := newTestClient(4).Get(context.Background(), "/rest/api/space/KEY", &result); err != nil {
+			t.Fatalf("expected request to succeed after retries, got: %s", err)
+		}
+		if result["name"] != "Space" {
+			t.Fatalf("unexpected response: %v", result)
+		}
+		if attempts := svr.RequestCount() - start; attempts != 3 {
+			t.Fatalf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("service unavailable", func(t *testing.T) {
+		start := svr.RequestCount()
+		svr.InjectResponses(1, http.StatusServiceUnavailable, nil)
+		if err := newTestClient(4).Get(context.Background(), "/rest/api/space/KEY", nil); err != nil {
+			t.Fatalf("expected request to succeed after retries, got: %s", err)
+		}
+		if attempts := svr.RequestCount() - start; attempts != 2 {
+			t.Fatalf("expected 2 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		start := svr.RequestCount()
+		svr.InjectResponses(3, http.StatusServiceUnavailable, nil)
+		if err := newTestClient(2).Get(context.Background(), "/rest/api/space/KEY", nil); err == nil {
+			t.Fatalf("expected request to fail once the retries are exhausted")
+		}
+		if attempts := svr.RequestCount() - start; attempts != 3 {
+			t.Fatalf("expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("post is not retried on internal server error", func(t *testing.T) {
+		start := svr.RequestCount()
+		svr.InjectResponses(1, http.StatusInternalServerError, nil)
+		if err := newTestClient(4).Post(context.Background(), "/rest/api/space/NEW", map[string]string{"id": "NEW"}, nil, nil); err == nil {
+			t.Fatalf("expected POST to fail without being retried")
+		}
+		if attempts := svr.RequestCount() - start; attempts != 1 {
+			t.Fatalf("expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("cancelled context aborts the retry wait", func(t *testing.T) {
+		svr.InjectResponses(1, http.StatusTooManyRequests, map[string]string{"Retry-After": "30"})
+		client := newTestClient(4)
+		client.retry.maxWait = time.Minute
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		started := time.Now()
+		if err := client.Get(ctx, "/rest/api/space/KEY", nil); err == nil {
+			t.Fatalf("expected request to fail once the context is cancelled")
+		}
+		if elapsed := time.Since(started); elapsed > 5*time.Second {
+			t.Fatalf("expected cancellation to interrupt the retry wait, took %s", elapsed)
+		}
+	})
+
+	t.Run("replays request body", func(t *testing.T) {
+		svr.InjectResponses(1, http.StatusTooManyRequests, map[string]string{"Retry-After": "0"})
+		body := map[string]string{"id": "REPLAY", "name": "Replayed"}
+		if err := newTestClient(4).Post(context.Background(), "/rest/api/space/REPLAY", body, nil, nil); err != nil {
+			t.Fatalf("expected request to succeed after retries, got: %s", err)
+		}
+		if objects["REPLAY"]["name"] != "Replayed" {
+			t.Fatalf("expected the retried request to carry the full body, server has: %v", objects["REPLAY"])
+		}
+	})
+}
+
+func TestClientErrors(t *testing.T) {
+	svr := newTestServer(map[string]map[string]interface{}{})
+	defer svr.Shutdown()
+
+	svr.InjectResponses(1, http.StatusNotFound, nil)
+	err := newTestClient(0).Get(context.Background(), "/rest/api/space/MISSING", nil)
+	if !IsNotFound(err) {
+		t.Fatalf("expected a not found error, got: %v", err)
+	}
+	var apiError *APIError
+	if !errors.As(err, &apiError) || apiError.Method != "GET" || apiError.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected an APIError describing the request, got: %#v", err)
+	}
+
+	svr.InjectResponses(1, http.StatusConflict, nil)
+	err = newTestClient(0).Post(context.Background(), "/rest/api/space/DUPLICATE", map[string]string{"id": "DUPLICATE"}, nil, nil)
+	if !errors.Is(err, ErrConflict) || IsNotFound(err) {
+		t.Fatalf("expected a conflict error, got: %v", err)
+	}
+}
+
+func TestErrorResponse(t *testing.T) {
+	var response ErrorResponse
+	body := `{"statusCode":400,"data":{"authorized":true,"valid":false,"errors":[{"message":{"key":"space.key.invalid","translation":"Space key is invalid"}}],"successful":false},"message":"Could not create space","reason":"Bad Request"}`
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatal(err)
+	}
+	message := response.String()
+	for _, expected := range []string{"Could not create space", "Bad Request", "Space key is invalid", "Code: 400"} {
+		if !strings.Contains(message, expected) {
+			t.Fatalf("expected %q in error message, got: %s", expected, message)
+		}
+	}
+}
+
+func TestClientPostForm(t *testing.T) {
+	var filename, content, comment, token string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token = r.Header.Get("X-Atlassian-Token")
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		filename = header.Filename
+		content = string(data)
+		comment = r.FormValue("comment")
+		w.Write([]byte(`{"results":[{"id":"att1"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(&NewClientInput{Site: strings.TrimPrefix(server.URL, "http://")})
+	var result map[string]interface{}
+	err := client.PostForm(context.Background(), "/rest/api/content/1/child/attachment", "notes.txt", []byte("hello"), map[string]string{"comment": "first"}, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filename != "notes.txt" || content != "hello" || comment != "first" {
+		t.Fatalf("unexpected upload: filename=%q content=%q comment=%q", filename, content, comment)
+	}
+	if token != "nocheck" {
+		t.Fatalf("expected the XSRF check to be disabled for uploads, got %q", token)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := newRetryPolicy(4, 10*time.Second)
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	header := http.Header{}
+	header.Set("Retry-After", "3")
+	if wait, ok := serverRetryHint(header, now); !ok || wait != 3*time.Second {
+		t.Fatalf("expected Retry-After seconds to be honored, got %s", wait)
+	}
+
+	header = http.Header{}
+	header.Set("Retry-After", now.Add(5*time.Second).Format(http.TimeFormat))
+	if wait, ok := serverRetryHint(header, now); !ok || wait != 5*time.Second {
+		t.Fatalf("expected Retry-After date to be honored, got %s", wait)
+	}
+
+	header = http.Header{}
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", "2023-01-01T12:01Z")
+	if wait, ok := serverRetryHint(header, now); !ok || wait != time.Minute {
+		t.Fatalf("expected X-RateLimit-Reset to be honored, got %s", wait)
+	}
+
+	header = http.Header{}
+	header.Set("Retry-After", "60")
+	resp := &http.Response{Header: header}
+	if wait := policy.delay(0, resp); wait != 10*time.Second {
+		t.Fatalf("expected server hint to be capped at the max wait, got %s", wait)
+	}
+
+	for attempt := 0; attempt < 8; attempt++ {
+		wait := policy.delay(attempt, nil)
+		if wait <= 0 || wait > 10*time.Second {
+			t.Fatalf("backoff for attempt %d out of range: %s", attempt, wait)
+		}
+	}
+}
+
+func TestClientIsCloud(t *testing.T) {
+	cases := map[string]bool{
+		"example.atlassian.net":  true,
+		"api.atlassian.com":      true,
+		"confluence.example.com": false,
+		"localhost:8090":         false,
+	}
+	for site, expected := range cases {
+		client := NewClient(&NewClientInput{Site: site})
+		if actual := client.IsCloud(); actual != expected {
+			t.Errorf("IsCloud() for %q = %v, expected %v", site, actual, expected)
+		}
+	}
+}
+
+func TestClientAPIVersion(t *testing.T) {
+	cases := []struct {
+		site     string
+		version  APIVersion
+		expected APIVersion
+	}{
+		{site: "example.atlassian.net", expected: APIVersionV2},
+		{site: "confluence.example.com", expected: APIVersionV1},
+		{site: "example.atlassian.net", version: APIVersionV1, expected: APIVersionV1},
+		{site: "confluence.example.com", version: APIVersionV2, expected: APIVersionV2},
+	}
+	for _, c := range cases {
+		client := NewClient(&NewClientInput{Site: c.site, APIVersion: c.version})
+		if actual := client.APIVersion(); actual != c.expected {
+			t.Errorf("APIVersion() for %q configured as %q = %q, expected %q", c.site, c.version, actual, c.expected)
+		}
+	}
+
+	if _, err := ParseAPIVersion("v3"); err == nil {
+		t.Error("expected an error for an unsupported API version")
+	}
+}
@@ -0,0 +1,81 @@
+package helpers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultCredentialsFile is used when no credentials file is configured
+const DefaultCredentialsFile = "~/.confluence/credentials"
+
+// DefaultProfile is used when no profile is configured
+const DefaultProfile = "default"
+
+// ErrProfileNotFound is returned when the credentials file has no section for the profile
+var ErrProfileNotFound = errors.New("profile not found")
+
+// ReadCredentialsFile returns the settings of a profile in an INI style credentials file:
+//
+//	[default]
+//	site  = example.atlassian.net
+//	user  = jdoe@example.com
+//	token = secret
+func ReadCredentialsFile(path string, profile string) (map[string]string, error) {
+	path, err := ExpandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	profiles := make(map[string]map[string]string)
+	var current map[string]string
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if profiles[name] == nil {
+				profiles[name] = make(map[string]string)
+			}
+			current = profiles[name]
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found || current == nil {
+			return nil, fmt.Errorf("%s:%d: expected a [profile] header or a key = value pair", path, number)
+		}
+		current[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	settings, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q in %s", ErrProfileNotFound, profile, path)
+	}
+	return settings, nil
+}
+
+// ExpandHome replaces a leading ~ with the home directory of the current user
+func ExpandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadCredentialsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	content := `# Confluence credentials
+[default]
+site  = example.atlassian.net
+user  = jdoe@example.com
+token = "secret"
+
+[server]
+site = confluence.example.com
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := ReadCredentialsFile(path, DefaultProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings["site"] != "example.atlassian.net" || settings["user"] != "jdoe@example.com" || settings["token"] != "secret" {
+		t.Fatalf("unexpected settings: %v", settings)
+	}
+
+	settings, err = ReadCredentialsFile(path, "server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings["site"] != "confluence.example.com" || settings["token"] != "" {
+		t.Fatalf("unexpected settings: %v", settings)
+	}
+
+	if _, err := ReadCredentialsFile(path, "missing"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("expected a profile not found error, got: %v", err)
+	}
+}
+
+func TestReadCredentialsFileMalformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte("site = example.atlassian.net\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadCredentialsFile(path, DefaultProfile); err == nil {
+		t.Fatal("expected an error for a key outside of a profile")
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	expanded, err := ExpandHome("~/.confluence/credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expanded != filepath.Join(home, ".confluence", "credentials") {
+		t.Fatalf("unexpected path: %s", expanded)
+	}
+	if expanded, _ := ExpandHome("/etc/credentials"); expanded != "/etc/credentials" {
+		t.Fatalf("expected absolute paths to be kept, got: %s", expanded)
+	}
+}
@@ -0,0 +1,119 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors used to classify failed requests with errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// APIError is returned when Confluence answers with an unexpected status code.
+// Path has sensitive query parameters scrubbed and RequestBody is only set in debug mode, with sensitive
+// fields scrubbed as well.
+type APIError struct {
+	Method      string
+	Path        string
+	StatusCode  int
+	Status      string
+	RequestBody string
+	Response    *ErrorResponse
+}
+
+// ErrorResponse is the error body returned by the Confluence REST API
+type ErrorResponse struct {
+	StatusCode int        `json:"statusCode,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+	Data       *ErrorData `json:"data,omitempty"`
+}
+
+// ErrorData is part of ErrorResponse
+type ErrorData struct {
+	Authorized bool          `json:"authorized"`
+	Valid      bool          `json:"valid"`
+	Successful bool          `json:"successful"`
+	Errors     []ErrorDetail `json:"errors,omitempty"`
+}
+
+// ErrorDetail is part of ErrorData
+type ErrorDetail struct {
+	Message ErrorMessage `json:"message"`
+}
+
+// ErrorMessage is part of ErrorDetail
+type ErrorMessage struct {
+	Key         string        `json:"key,omitempty"`
+	Args        []interface{} `json:"args,omitempty"`
+	Translation string        `json:"translation,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	responseBody := "Could not decode error"
+	if e.Response != nil {
+		responseBody = e.Response.String()
+	}
+	request := fmt.Sprintf("%s %s", e.Method, e.Path)
+	if e.RequestBody != "" {
+		request = fmt.Sprintf("%s\n%s", request, e.RequestBody)
+	}
+	return fmt.Sprintf("%s\n\n%s\n\n%s", e.Status, request, responseBody)
+}
+
+// Is allows errors.Is to match an APIError against the sentinel errors of this package
+func (e *APIError) Is(target error) bool {
+	return e.kind() == target
+}
+
+func (e *APIError) kind() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation
+	}
+	return nil
+}
+
+// IsNotFound reports whether err was caused by a 404 response
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func (e *ErrorResponse) String() string {
+	var messages []string
+	if e.Message != "" {
+		messages = append(messages, e.Message)
+	}
+	if e.Reason != "" {
+		messages = append(messages, fmt.Sprintf("Reason: %s", e.Reason))
+	}
+	if e.Data != nil {
+		for _, detail := range e.Data.Errors {
+			if detail.Message.Translation != "" {
+				messages = append(messages, detail.Message.Translation)
+			} else if detail.Message.Key != "" {
+				messages = append(messages, detail.Message.Key)
+			}
+		}
+	}
+	return fmt.Sprintf("%s\nCode: %d",
+		strings.Join(messages, "\n"), e.StatusCode)
+}
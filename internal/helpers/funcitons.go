@@ -25,7 +25,21 @@ func MoveToFirstPositionOfSlice[K comparable](slice []K, item K) []K {
 	}
 	for p, x := range slice {
 		if x == item {
-			slice = append([]K{item}, append((slice)[:p], (slice)[p+1:]...)...)
+			// Copy the remaining items, appending to slice[:p] would overwrite the caller's slice
+			slice = append(append([]K{item}, slice[:p]...), slice[p+1:]...)
+			break
+		}
+	}
+	return slice
+}
+
+func MoveToLastPositionOfSlice[K comparable](slice []K, item K) []K {
+	if len(slice) == 0 || slice[len(slice)-1] == item {
+		return slice
+	}
+	for p, x := range slice {
+		if x == item {
+			slice = append(append(append([]K{}, slice[:p]...), slice[p+1:]...), item)
 			break
 		}
 	}
@@ -0,0 +1,40 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMoveToFirstPositionOfSlice(t *testing.T) {
+	actual := MoveToFirstPositionOfSlice([]string{"create:page", "delete:page", "read:space"}, "read:space")
+	expected := []string{"read:space", "create:page", "delete:page"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	input := []string{"create:page", "read:space", "delete:page"}
+	MoveToFirstPositionOfSlice(input, "read:space")
+	if !reflect.DeepEqual(input, []string{"create:page", "read:space", "delete:page"}) {
+		t.Fatalf("expected the input to be left unchanged, got %v", input)
+	}
+}
+
+func TestMoveToLastPositionOfSlice(t *testing.T) {
+	cases := []struct {
+		input    []string
+		expected []string
+	}{
+		{[]string{"read:space", "create:page", "delete:page"}, []string{"create:page", "delete:page", "read:space"}},
+		{[]string{"create:page", "read:space", "delete:page"}, []string{"create:page", "delete:page", "read:space"}},
+		{[]string{"create:page", "read:space"}, []string{"create:page", "read:space"}},
+		{[]string{"create:page"}, []string{"create:page"}},
+		{[]string{}, []string{}},
+	}
+	for _, c := range cases {
+		input := append([]string{}, c.input...)
+		actual := MoveToLastPositionOfSlice(input, "read:space")
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Fatalf("MoveToLastPositionOfSlice(%v) = %v, expected %v", c.input, actual, c.expected)
+		}
+	}
+}
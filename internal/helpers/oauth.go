@@ -0,0 +1,181 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTokenURL is the token endpoint of Atlassian service accounts
+const DefaultTokenURL = "https://api.atlassian.com/oauth/token"
+
+// CloudGatewayHost routes OAuth 2.0 requests to Confluence Cloud sites
+const CloudGatewayHost = "api.atlassian.com"
+
+// tokenExpiryLeeway refreshes access tokens before they expire during long applies
+const tokenExpiryLeeway = time.Minute
+
+// defaultTokenLifetime is assumed when the token endpoint does not say when a token expires
+const defaultTokenLifetime = time.Hour
+
+// TokenSource provides the access tokens for bearer authentication
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// BearerTokenSource authenticates every request with the current token of the source
+func BearerTokenSource(source TokenSource) Authenticator {
+	return bearerTokenSource{source: source}
+}
+
+type bearerTokenSource struct {
+	source TokenSource
+}
+
+func (a bearerTokenSource) Authenticate(ctx context.Context, req *http.Request) error {
+	token, err := a.source.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Invalidate drops the cached token of the source, if it caches one
+func (a bearerTokenSource) Invalidate() {
+	if invalidator, ok := a.source.(Invalidator); ok {
+		invalidator.Invalidate()
+	}
+}
+
+// String keeps the token out of logs and error messages
+func (a bearerTokenSource) String() string {
+	return "bearer token source"
+}
+
+// ClientCredentials fetches OAuth 2.0 access tokens with the client credentials grant and caches them
+// until shortly before they expire
+type ClientCredentials struct {
+	clientId     string
+	clientSecret string
+	tokenURL     string
+	client       *http.Client
+	now          func() time.Time
+
+	mutex  sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewClientCredentials returns a token source for the given OAuth 2.0 client. Tokens are fetched through
+// the given transport, or the default transport when it is nil.
+func NewClientCredentials(clientId string, clientSecret string, tokenURL string, transport http.RoundTripper) *ClientCredentials {
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	return &ClientCredentials{
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		tokenURL:     tokenURL,
+		client:       &http.Client{Transport: transport, Timeout: DefaultTimeout},
+		now:          time.Now,
+	}
+}
+
+// String keeps the client secret out of logs and error messages
+func (c *ClientCredentials) String() string {
+	return fmt.Sprintf("client credentials for %q", c.clientId)
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Token returns the cached access token or fetches a new one when it is about to expire
+func (c *ClientCredentials) Token(ctx context.Context) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.token != "" && c.now().Add(tokenExpiryLeeway).Before(c.expiry) {
+		return c.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("client_id", c.clientId)
+	form.Set("client_secret", c.clientSecret)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not fetch an access token from %s: %w", c.tokenURL, err)
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&token)
+	if resp.StatusCode != http.StatusOK {
+		if token.Error != "" {
+			return "", fmt.Errorf("could not fetch an access token from %s: %s: %s %s", c.tokenURL, resp.Status, token.Error, token.ErrorDescription)
+		}
+		return "", fmt.Errorf("could not fetch an access token from %s: %s", c.tokenURL, resp.Status)
+	}
+	if decodeErr != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("could not fetch an access token from %s: the response contains no access token", c.tokenURL)
+	}
+
+	lifetime := time.Duration(token.ExpiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = defaultTokenLifetime
+	}
+	c.token = token.AccessToken
+	c.expiry = c.now().Add(lifetime)
+	return c.token, nil
+}
+
+// Invalidate drops the cached token, e.g. after it was revoked, the next call to Token fetches a new one
+func (c *ClientCredentials) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.token = ""
+}
+
+type tenantInfo struct {
+	CloudId string `json:"cloudId"`
+}
+
+// DiscoverCloudId looks up the cloud id of an atlassian.net site, which is needed to route requests
+// through the API gateway. The site is given as base URL, e.g. https://example.atlassian.net
+func DiscoverCloudId(ctx context.Context, site string, transport http.RoundTripper) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(site, "/")+"/_edge/tenant_info", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := (&http.Client{Transport: transport, Timeout: DefaultTimeout}).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not look up the cloud id of %s: %w", site, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("could not look up the cloud id of %s: %s", site, resp.Status)
+	}
+	var info tenantInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil || info.CloudId == "" {
+		return "", fmt.Errorf("could not look up the cloud id of %s: the response contains no cloud id", site)
+	}
+	return info.CloudId, nil
+}
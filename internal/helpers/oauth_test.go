@@ -0,0 +1,181 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientCredentials(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.FormValue("grant_type") != "client_credentials" || r.FormValue("client_id") != "client" || r.FormValue("client_secret") != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"access_denied","error_description":"Unauthorized"}`))
+			return
+		}
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, requests)
+	}))
+	defer server.Close()
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	source := NewClientCredentials("client", "s3cr3t", server.URL, nil)
+	source.now = func() time.Time { return now }
+
+	token, err := source.Token(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "token-1" {
+		t.Fatalf("unexpected token: %s", token)
+	}
+
+	// Cached until shortly before it expires
+	now = now.Add(58 * time.Minute)
+	if token, _ = source.Token(context.Background()); token != "token-1" || requests != 1 {
+		t.Fatalf("expected the cached token, got %s after %d requests", token, requests)
+	}
+	now = now.Add(90 * time.Second)
+	if token, _ = source.Token(context.Background()); token != "token-2" || requests != 2 {
+		t.Fatalf("expected a refreshed token, got %s after %d requests", token, requests)
+	}
+
+	// Errors name the endpoint but never the secret
+	source = NewClientCredentials("client", "wrong-s3cr3t", server.URL, nil)
+	_, err = source.Token(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "access_denied") || strings.Contains(err.Error(), "s3cr3t") {
+		t.Fatalf("expected an error without the secret, got: %v", err)
+	}
+	if strings.Contains(fmt.Sprint(source), "s3cr3t") {
+		t.Fatalf("expected the token source to hide the secret, got %s", source)
+	}
+}
+
+func TestClientUsesTokenSource(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			w.Write([]byte(`{"access_token":"access","expires_in":3600}`))
+		default:
+			header = r.Header.Get("Authorization")
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	auth := BearerTokenSource(NewClientCredentials("client", "s3cr3t", server.URL+"/oauth/token", nil))
+	client := NewClient(&NewClientInput{Site: strings.TrimPrefix(server.URL, "http://"), Auth: auth})
+	if err := client.Get(context.Background(), "/rest/api/space", nil); err != nil {
+		t.Fatal(err)
+	}
+	if header != "Bearer access" {
+		t.Fatalf("expected the fetched access token, got %q", header)
+	}
+}
+
+func TestClientCredentialsWithoutExpiry(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer"}`, requests)
+	}))
+	defer server.Close()
+
+	now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	source := NewClientCredentials("client", "s3cr3t", server.URL, nil)
+	source.now = func() time.Time { return now }
+
+	source.Token(context.Background())
+	now = now.Add(30 * time.Minute)
+	if token, _ := source.Token(context.Background()); token != "token-1" || requests != 1 {
+		t.Fatalf("expected the token to be cached for the default lifetime, got %s after %d requests", token, requests)
+	}
+}
+
+func TestClientReauthenticatesOnUnauthorized(t *testing.T) {
+	tokens, calls := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth/token":
+			tokens++
+			fmt.Fprintf(w, `{"access_token":"access-%d","expires_in":3600}`, tokens)
+		default:
+			calls++
+			// The first token was revoked on the server
+			if r.Header.Get("Authorization") == "Bearer access-1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	auth := BearerTokenSource(NewClientCredentials("client", "s3cr3t", server.URL+"/oauth/token", nil))
+	client := NewClient(&NewClientInput{Site: strings.TrimPrefix(server.URL, "http://"), Auth: auth})
+	if err := client.Get(context.Background(), "/rest/api/space", nil); err != nil {
+		t.Fatal(err)
+	}
+	if tokens != 2 || calls != 2 {
+		t.Fatalf("expected one new token and one retried request, got %d tokens and %d requests", tokens, calls)
+	}
+
+	// A token that is rejected again is not fetched over and over
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			tokens++
+			fmt.Fprintf(w, `{"access_token":"access-%d","expires_in":3600}`, tokens)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	if err := client.Get(context.Background(), "/rest/api/space", nil); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected an unauthorized error, got: %v", err)
+	}
+	if tokens != 3 {
+		t.Fatalf("expected a single new token, got %d tokens", tokens)
+	}
+}
+
+func TestClientCloudGateway(t *testing.T) {
+	client := NewClient(&NewClientInput{Site: "example.atlassian.net", SiteUseTLS: true, Context: "/wiki", CloudId: "cloud-123"})
+	u, err := client.baseURL.Parse(client.basePath + "/rest/api/space")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "https://api.atlassian.com/ex/confluence/cloud-123/wiki/rest/api/space" {
+		t.Fatalf("unexpected gateway URL: %s", u)
+	}
+	if !client.IsCloud() {
+		t.Fatal("expected the gateway to be treated as Confluence Cloud")
+	}
+}
+
+func TestDiscoverCloudId(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_edge/tenant_info" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"cloudId":"cloud-123"}`))
+	}))
+	defer server.Close()
+
+	cloudId, err := DiscoverCloudId(context.Background(), server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cloudId != "cloud-123" {
+		t.Fatalf("unexpected cloud id: %s", cloudId)
+	}
+	if _, err := DiscoverCloudId(context.Background(), server.URL+"/missing", nil); err == nil {
+		t.Fatal("expected an error when the tenant info is missing")
+	}
+}
@@ -0,0 +1,166 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageLimit is requested from list endpoints unless the path sets a limit. The server may return
+// fewer results per page.
+const DefaultPageLimit = 200
+
+// Page is a single page of a list endpoint. The v1 API pages by start and limit, the v2 API by a cursor
+// that is only ever exposed through the next link.
+type Page[T any] struct {
+	Results   []T       `json:"results"`
+	Start     int       `json:"start"`
+	Limit     int       `json:"limit"`
+	Size      int       `json:"size"`
+	TotalSize int       `json:"totalSize"`
+	Links     PageLinks `json:"_links"`
+}
+
+// PageLinks is part of Page
+type PageLinks struct {
+	Base    string `json:"base,omitempty"`
+	Context string `json:"context,omitempty"`
+	Next    string `json:"next,omitempty"`
+}
+
+// Paginator streams the results of a list endpoint and fetches the next page only when it is needed:
+//
+//	members := helpers.Paginate[transferobjects.Member](client, path)
+//	for members.Next(ctx) {
+//		member := members.Value()
+//		...
+//	}
+//	err := members.Err()
+//
+// It follows _links.next when the server returns one and falls back to start and limit otherwise.
+type Paginator[T any] struct {
+	client    *Client
+	path      string
+	limit     int
+	start     int
+	results   []T
+	index     int
+	totalSize int
+	links     PageLinks
+	done      bool
+	err       error
+}
+
+// Paginate returns a paginator for the list endpoint at the given path
+func Paginate[T any](client *Client, path string) *Paginator[T] {
+	p := &Paginator[T]{client: client, path: path, limit: DefaultPageLimit, index: -1}
+	base, query, _ := strings.Cut(path, "?")
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return p
+	}
+	p.start, _ = strconv.Atoi(values.Get("start"))
+	if limit, err := strconv.Atoi(values.Get("limit")); err == nil && limit > 0 {
+		p.limit = limit
+	} else {
+		values.Set("limit", strconv.Itoa(p.limit))
+		p.path = base + "?" + values.Encode()
+	}
+	return p
+}
+
+// Next advances to the next result and fetches the next page if needed. It returns false when there are
+// no more results, when a request failed or when the context is cancelled.
+func (p *Paginator[T]) Next(ctx context.Context) bool {
+	if p.err != nil {
+		return false
+	}
+	p.index++
+	for p.index >= len(p.results) {
+		if p.done {
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			p.err = err
+			return false
+		}
+		if err := p.fetch(ctx); err != nil {
+			p.err = err
+			return false
+		}
+	}
+	return true
+}
+
+// Value returns the current result
+func (p *Paginator[T]) Value() T {
+	return p.results[p.index]
+}
+
+// Err returns the error that stopped the paginator, if any
+func (p *Paginator[T]) Err() error {
+	return p.err
+}
+
+// TotalSize returns the total number of results reported by the server, or 0 when it is unknown
+func (p *Paginator[T]) TotalSize() int {
+	return p.totalSize
+}
+
+// Links returns the links of the most recently fetched page, list results link relative to its base and context
+func (p *Paginator[T]) Links() PageLinks {
+	return p.links
+}
+
+// All collects the remaining results
+func (p *Paginator[T]) All(ctx context.Context) ([]T, error) {
+	var results []T
+	for p.Next(ctx) {
+		results = append(results, p.Value())
+	}
+	return results, p.Err()
+}
+
+func (p *Paginator[T]) fetch(ctx context.Context) error {
+	var page Page[T]
+	if err := p.client.Get(ctx, p.path, &page); err != nil {
+		return err
+	}
+	p.results = page.Results
+	p.links = page.Links
+	p.index = 0
+	if page.TotalSize > 0 {
+		p.totalSize = page.TotalSize
+	}
+
+	if page.Links.Next != "" {
+		next := p.client.RelativePath(page.Links.Next)
+		if next == p.path {
+			return fmt.Errorf("pagination of %s did not advance", RedactPath(p.path))
+		}
+		p.path = next
+		return nil
+	}
+
+	// Without a next link only a full page means there may be more. The server may cap the limit, so the
+	// limit it reports wins over the requested one.
+	limit := p.limit
+	if page.Limit > 0 {
+		limit = page.Limit
+	}
+	p.start += len(page.Results)
+	if len(page.Results) == 0 || len(page.Results) < limit || (p.totalSize > 0 && p.start >= p.totalSize) {
+		p.done = true
+		return nil
+	}
+	base, query, _ := strings.Cut(p.path, "?")
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return err
+	}
+	values.Set("start", strconv.Itoa(p.start))
+	p.path = base + "?" + values.Encode()
+	return nil
+}
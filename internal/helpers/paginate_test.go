@@ -0,0 +1,156 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+type testItem struct {
+	Id int `json:"id"`
+}
+
+func newPaginateTestClient(t *testing.T, handler http.HandlerFunc, context string) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	serverURL, _ := url.Parse(server.URL)
+	return NewClient(&NewClientInput{Site: serverURL.Host, Context: context})
+}
+
+func writePage(w http.ResponseWriter, page Page[testItem]) {
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func TestPaginateOffset(t *testing.T) {
+	const total = 45
+	requests := 0
+	client := newPaginateTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		// The server caps the requested limit
+		if limit > 10 {
+			limit = 10
+		}
+		page := Page[testItem]{Start: start, Limit: limit}
+		for i := start; i < total && i < start+limit; i++ {
+			page.Results = append(page.Results, testItem{Id: i})
+		}
+		page.Size = len(page.Results)
+		writePage(w, page)
+	}, "")
+
+	items, err := Paginate[testItem](client, "/rest/api/items?expand=all").All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != total || items[total-1].Id != total-1 {
+		t.Fatalf("expected %d items, got %v", total, items)
+	}
+	if requests != 5 {
+		t.Fatalf("expected 5 requests, got %d", requests)
+	}
+}
+
+func TestPaginateNextLinks(t *testing.T) {
+	tests := map[string]struct {
+		context string
+		next    func(page int) string
+	}{
+		"v1": {
+			context: "/wiki",
+			next:    func(page int) string { return fmt.Sprintf("/rest/api/items?limit=2&start=%d", page*2) },
+		},
+		"v2 cursor": {
+			context: "/wiki",
+			next:    func(page int) string { return fmt.Sprintf("/wiki/api/v2/items?limit=2&cursor=c%d", page) },
+		},
+		"absolute": {
+			next: func(page int) string { return fmt.Sprintf("https://example.com/api/v2/items?cursor=c%d", page) },
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var paths []string
+			client := newPaginateTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.RequestURI())
+				page := len(paths)
+				response := Page[testItem]{Results: []testItem{{Id: page*2 - 1}, {Id: page * 2}}}
+				// Short pages only end the pagination when there is no next link
+				if page >= 2 {
+					response.Results = response.Results[:1]
+				}
+				if page < 3 {
+					response.Links.Next = test.next(page)
+				}
+				writePage(w, response)
+			}, test.context)
+
+			items, err := Paginate[testItem](client, "/api/v2/items?limit=2").All(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(items) != 4 || len(paths) != 3 {
+				t.Fatalf("expected 4 items from 3 pages, got %v from %v", items, paths)
+			}
+			for _, path := range paths[1:] {
+				if path[:len(test.context)+1] != test.context+"/" {
+					t.Fatalf("expected the context to be kept once, got %s", path)
+				}
+			}
+			if expected := test.context + client.RelativePath(test.next(2)); paths[2] != expected {
+				t.Fatalf("expected %s, got %s", expected, paths[2])
+			}
+		})
+	}
+}
+
+func TestPaginateStops(t *testing.T) {
+	t.Run("next link does not advance", func(t *testing.T) {
+		client := newPaginateTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			writePage(w, Page[testItem]{Results: []testItem{{Id: 1}}, Links: PageLinks{Next: "/items?limit=200"}})
+		}, "")
+		if _, err := Paginate[testItem](client, "/items").All(context.Background()); err == nil {
+			t.Fatal("expected an error for a next link pointing to the same page")
+		}
+	})
+
+	t.Run("early exit", func(t *testing.T) {
+		requests := 0
+		client := newPaginateTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			writePage(w, Page[testItem]{Results: []testItem{{Id: requests}}, Links: PageLinks{Next: fmt.Sprintf("/items?page=%d", requests)}})
+		}, "")
+		items := Paginate[testItem](client, "/items")
+		for items.Next(context.Background()) {
+			if items.Value().Id == 2 {
+				break
+			}
+		}
+		if requests != 2 {
+			t.Fatalf("expected pages to be fetched on demand, got %d requests", requests)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		requests := 0
+		client := newPaginateTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			writePage(w, Page[testItem]{Results: []testItem{{Id: requests}}, Links: PageLinks{Next: fmt.Sprintf("/items?page=%d", requests)}})
+		}, "")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		items := Paginate[testItem](client, "/items")
+		for items.Next(ctx) {
+			cancel()
+		}
+		if items.Err() != context.Canceled || requests != 1 {
+			t.Fatalf("expected the pagination to stop after the cancellation, got %v after %d requests", items.Err(), requests)
+		}
+	})
+}
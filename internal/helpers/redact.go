@@ -0,0 +1,82 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces sensitive values in error messages
+const Redacted = "REDACTED"
+
+// sensitiveKeys are matched case-insensitively against JSON fields and query parameters
+var sensitiveKeys = []string{"password", "token", "secret", "authorization", "credential", "apikey", "api_key", "cookie"}
+
+func isSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactBody scrubs sensitive fields from a JSON request body. Bodies that are not JSON, such as
+// attachment uploads, are replaced by their size.
+func RedactBody(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("<%d bytes omitted>", len(body))
+	}
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes omitted>", len(body))
+	}
+	return string(redacted)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveKey(key) {
+				v[key] = Redacted
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, element := range v {
+			v[i] = redactValue(element)
+		}
+	}
+	return value
+}
+
+// RedactPath scrubs sensitive query parameters from a request path
+func RedactPath(path string) string {
+	base, query, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return base + "?" + Redacted
+	}
+	changed := false
+	for key := range values {
+		if isSensitiveKey(key) {
+			values[key] = []string{Redacted}
+			changed = true
+		}
+	}
+	if !changed {
+		return path
+	}
+	return base + "?" + values.Encode()
+}
@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactBody(t *testing.T) {
+	body := `{"name":"Runbooks","password":"hunter2","nested":{"apiToken":"abc"},"list":[{"client_secret":"xyz","key":"KEY"}]}`
+	redacted := RedactBody([]byte(body))
+	for _, secret := range []string{"hunter2", "abc", "xyz"} {
+		if strings.Contains(redacted, secret) {
+			t.Fatalf("expected %q to be scrubbed, got: %s", secret, redacted)
+		}
+	}
+	for _, kept := range []string{"Runbooks", "KEY", Redacted} {
+		if !strings.Contains(redacted, kept) {
+			t.Fatalf("expected %q in the body, got: %s", kept, redacted)
+		}
+	}
+
+	if redacted := RedactBody([]byte("--boundary\r\nfile contents")); redacted != "<25 bytes omitted>" {
+		t.Fatalf("expected non JSON bodies to be omitted, got: %s", redacted)
+	}
+	if redacted := RedactBody(nil); redacted != "" {
+		t.Fatalf("expected an empty body, got: %s", redacted)
+	}
+}
+
+func TestRedactPath(t *testing.T) {
+	cases := map[string]string{
+		"/rest/api/space/KEY":                          "/rest/api/space/KEY",
+		"/rest/api/space?limit=10":                     "/rest/api/space?limit=10",
+		"/rest/api/group?name=a&access_token=s3cr3t":   "/rest/api/group?access_token=REDACTED&name=a",
+		"https://example.com/wiki?os_password=hunter2": "https://example.com/wiki?os_password=REDACTED",
+	}
+	for path, expected := range cases {
+		if actual := RedactPath(path); actual != expected {
+			t.Errorf("RedactPath(%q) = %q, expected %q", path, actual, expected)
+		}
+	}
+}
+
+func TestClientErrorsAreRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"statusCode":400,"message":"Invalid space"}`))
+	}))
+	defer server.Close()
+	site := strings.TrimPrefix(server.URL, "http://")
+	body := map[string]interface{}{"name": "Runbooks", "password": "hunter2", "credentials": map[string]string{"token": "abc"}}
+	secrets := []string{"s3cr3t-token", "hunter2", "abc", "qwerty"}
+
+	for _, debug := range []bool{false, true} {
+		client := NewClient(&NewClientInput{Site: site, Auth: BearerToken("s3cr3t-token"), Debug: debug})
+		err := client.Post(context.Background(), "/rest/api/space?token=qwerty", body, nil, nil)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		message := err.Error()
+		for _, secret := range secrets {
+			if strings.Contains(message, secret) {
+				t.Fatalf("debug=%v: expected %q to be scrubbed, got: %s", debug, secret, message)
+			}
+		}
+		if !strings.Contains(message, "Invalid space") || !strings.Contains(message, "POST /rest/api/space") {
+			t.Fatalf("debug=%v: expected the request and the Confluence error, got: %s", debug, message)
+		}
+		if strings.Contains(message, "Runbooks") != debug {
+			t.Fatalf("debug=%v: expected the request body only in debug mode, got: %s", debug, message)
+		}
+	}
+}
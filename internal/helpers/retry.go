@@ -0,0 +1,142 @@
+package helpers
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a request is retried when nothing else is configured
+	DefaultMaxRetries = 4
+	// DefaultMaxRetryWait is the longest the client waits between two attempts when nothing else is configured
+	DefaultMaxRetryWait = 30 * time.Second
+	// DefaultMinRetryWait is the base delay of the exponential backoff
+	DefaultMinRetryWait = 1 * time.Second
+)
+
+// retryPolicy decides whether and when a failed request is sent again
+type retryPolicy struct {
+	maxRetries int
+	minWait    time.Duration
+	maxWait    time.Duration
+}
+
+func newRetryPolicy(maxRetries int, maxWait time.Duration) *retryPolicy {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	if maxWait <= 0 {
+		maxWait = DefaultMaxRetryWait
+	}
+	minWait := DefaultMinRetryWait
+	if minWait > maxWait {
+		minWait = maxWait
+	}
+	return &retryPolicy{
+		maxRetries: maxRetries,
+		minWait:    minWait,
+		maxWait:    maxWait,
+	}
+}
+
+// shouldRetry reports whether the outcome of the given attempt is worth another try.
+// Rate limiting (429) and 503 are always retried because Confluence did not process the request.
+// Other 5xx responses and transport errors are only retried for idempotent methods, so a POST
+// that may have been applied is never sent twice.
+func (p *retryPolicy) shouldRetry(method string, resp *http.Response, err error, attempt int) bool {
+	if attempt >= p.maxRetries {
+		return false
+	}
+	idempotent := method != "POST"
+	if err != nil {
+		return idempotent
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// delay returns how long to wait before the next attempt. Server hints (Retry-After,
+// X-RateLimit-Reset) take precedence over the exponential backoff, everything is capped at maxWait.
+func (p *retryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if wait, ok := serverRetryHint(resp.Header, time.Now()); ok {
+			return p.capped(wait)
+		}
+	}
+	return p.capped(p.backoff(attempt))
+}
+
+// backoff computes an exponential delay with jitter in the interval [base/2, base)
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	base := float64(p.minWait) * math.Pow(2, float64(attempt))
+	if base > float64(p.maxWait) {
+		base = float64(p.maxWait)
+	}
+	half := base / 2
+	return time.Duration(half + rand.Float64()*half)
+}
+
+func (p *retryPolicy) capped(wait time.Duration) time.Duration {
+	if wait < 0 {
+		return 0
+	}
+	if wait > p.maxWait {
+		return p.maxWait
+	}
+	return wait
+}
+
+// serverRetryHint extracts the delay requested by the server, if any
+func serverRetryHint(header http.Header, now time.Time) (time.Duration, bool) {
+	if value := header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return date.Sub(now), true
+		}
+	}
+	if header.Get("X-RateLimit-Remaining") == "0" || header.Get("X-RateLimit-Reset") != "" {
+		if reset, ok := parseRateLimitReset(header.Get("X-RateLimit-Reset")); ok {
+			return reset.Sub(now), true
+		}
+	}
+	return 0, false
+}
+
+// parseRateLimitReset understands the ISO 8601 timestamps sent by Atlassian Cloud as well as unix epoch seconds
+func parseRateLimitReset(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02T15:04Z"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(epoch, 0), true
+	}
+	return time.Time{}, false
+}
+
+type noRetryKey struct{}
+
+// withoutRetries sends the requests of the context once, e.g. for probes that have a fallback
+func withoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+func retriesDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noRetryKey{}).(bool)
+	return disabled
+}
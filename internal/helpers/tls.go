@@ -0,0 +1,94 @@
+package helpers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout is the default time limit of a single request
+const DefaultTimeout = 10 * time.Second
+
+// TransportInput configures the TLS and proxy settings of the connection to Confluence. Certificates and
+// keys are given either PEM encoded or as the path of a PEM file.
+type TransportInput struct {
+	// CABundle is trusted in addition to the system certificate pool
+	CABundle           string
+	ClientCertificate  string
+	ClientKey          string
+	InsecureSkipVerify bool
+	// ProxyURL overrides the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables
+	ProxyURL string
+}
+
+// NewTransport returns an HTTP transport with the given TLS and proxy settings
+func NewTransport(input *TransportInput) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: input.InsecureSkipVerify,
+	}
+
+	if input.CABundle != "" {
+		bundle, err := readPEM(input.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, errors.New("the CA bundle contains no PEM encoded certificates")
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	if input.ClientCertificate != "" || input.ClientKey != "" {
+		if input.ClientCertificate == "" || input.ClientKey == "" {
+			return nil, errors.New("client certificates need both the certificate and the private key")
+		}
+		certificate, err := readPEM(input.ClientCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the client certificate: %w", err)
+		}
+		key, err := readPEM(input.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not read the client key: %w", err)
+		}
+		pair, err := tls.X509KeyPair(certificate, key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{pair}
+	}
+
+	if input.ProxyURL != "" {
+		proxy, err := url.Parse(input.ProxyURL)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			// The parse error repeats the URL, which may contain proxy credentials
+			return nil, errors.New("the proxy URL must be an absolute URL like http://proxy.example.com:3128")
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return transport, nil
+}
+
+// readPEM returns inline PEM data as is and reads everything else from a file
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	path, err := ExpandHome(value)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(path)
+}
@@ -0,0 +1,132 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// LogSubsystem is the tflog subsystem of the request log. Its level can be set separately with
+// TF_LOG_PROVIDER_CONFLUENCE_HTTP.
+const LogSubsystem = "confluence_http"
+
+// credentialPattern masks credentials that slip into logged values, e.g. echoed by an error response
+var credentialPattern = regexp.MustCompile(`(?i)(basic|bearer)\s+[A-Za-z0-9._~+/=-]+`)
+
+type attemptKey struct{}
+
+// withAttempt records the retry attempt of a request for the request log
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+func attemptFromContext(ctx context.Context) int {
+	attempt, _ := ctx.Value(attemptKey{}).(int)
+	return attempt
+}
+
+// loggingTransport writes one DEBUG entry per request with the method, path, status, latency, retry
+// attempt and response size. Headers and bodies are only logged at TRACE, with credentials masked.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func newLoggingTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &loggingTransport{next: next}
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := tflog.NewSubsystem(req.Context(), LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER", LogSubsystem))
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, LogSubsystem, credentialPattern)
+	ctx = tflog.SubsystemSetField(ctx, LogSubsystem, "http_method", req.Method)
+	ctx = tflog.SubsystemSetField(ctx, LogSubsystem, "http_path", RedactPath(req.URL.RequestURI()))
+	ctx = tflog.SubsystemSetField(ctx, LogSubsystem, "http_attempt", attemptFromContext(req.Context()))
+
+	// Headers and bodies are only copied and redacted when they are written
+	trace := traceEnabled()
+	if trace {
+		var requestBody []byte
+		if req.Body != nil && req.GetBody != nil {
+			// GetBody hands out a fresh copy, so the body sent over the wire stays untouched
+			if body, err := req.GetBody(); err == nil {
+				requestBody, _ = io.ReadAll(body)
+				body.Close()
+			}
+		}
+		tflog.SubsystemTrace(ctx, LogSubsystem, "Sending HTTP request", map[string]interface{}{
+			"http_request_headers": redactHeaders(req.Header),
+			"http_request_body":    RedactBody(requestBody),
+		})
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	latency := time.Since(start)
+	if err != nil {
+		tflog.SubsystemDebug(ctx, LogSubsystem, "HTTP request failed", map[string]interface{}{
+			"http_latency_ms": latency.Milliseconds(),
+			"error":           err.Error(),
+		})
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	latency = time.Since(start)
+	if err != nil {
+		tflog.SubsystemDebug(ctx, LogSubsystem, "Reading HTTP response failed", map[string]interface{}{
+			"http_status":     resp.StatusCode,
+			"http_latency_ms": latency.Milliseconds(),
+			"error":           err.Error(),
+		})
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Received HTTP response", map[string]interface{}{
+		"http_status":        resp.StatusCode,
+		"http_latency_ms":    latency.Milliseconds(),
+		"http_response_size": len(responseBody),
+	})
+	if trace {
+		tflog.SubsystemTrace(ctx, LogSubsystem, "Received HTTP response body", map[string]interface{}{
+			"http_response_headers": redactHeaders(resp.Header),
+			"http_response_body":    RedactBody(responseBody),
+		})
+	}
+	return resp, nil
+}
+
+// traceEnabled reports whether the request log is at TRACE. The level is looked up in the same environment
+// variables as the log levels: the subsystem's first, then the provider's and then Terraform's.
+func traceEnabled() bool {
+	for _, name := range []string{"TF_LOG_PROVIDER_" + strings.ToUpper(LogSubsystem), "TF_LOG_PROVIDER", "TF_LOG"} {
+		if level := strings.ToUpper(strings.TrimSpace(os.Getenv(name))); level != "" {
+			return level == "TRACE" || (name == "TF_LOG" && level == "JSON")
+		}
+	}
+	return false
+}
+
+// redactHeaders flattens the headers for the log and replaces credentials and cookies
+func redactHeaders(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name, values := range header {
+		value := strings.Join(values, ", ")
+		if isSensitiveKey(name) {
+			value = Redacted
+		}
+		result[name] = value
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+// roundTripWithLog sends a request with credentials through the logging transport and returns the log
+func roundTripWithLog(t *testing.T) (string, []map[string]interface{}) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"password":"hunter2","name":"test"}` {
+			t.Errorf("expected the complete request body to reach the server, got %s", body)
+		}
+		w.Header().Set("Set-Cookie", "session=abc")
+		_, _ = w.Write([]byte(`{"id":"1","token":"hunter3"}`))
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := withAttempt(tflogtest.RootLogger(context.Background(), &output), 2)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/rest/api/group?access_token=abc", strings.NewReader(`{"password":"hunter2","name":"test"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer hunter4")
+
+	resp, err := (&http.Client{Transport: newLoggingTransport(nil)}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"id":"1","token":"hunter3"}` {
+		t.Errorf("expected the response body to stay readable, got %s", body)
+	}
+
+	log := output.String()
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return log, entries
+}
+
+func logEntry(entries []map[string]interface{}, message string) map[string]interface{} {
+	for _, entry := range entries {
+		if entry["@message"] == message {
+			return entry
+		}
+	}
+	return nil
+}
+
+func TestLoggingTransport(t *testing.T) {
+	for _, name := range []string{"TF_LOG_PROVIDER_CONFLUENCE_HTTP", "TF_LOG_PROVIDER", "TF_LOG"} {
+		t.Setenv(name, "")
+	}
+
+	t.Run("debug", func(t *testing.T) {
+		t.Setenv("TF_LOG_PROVIDER", "DEBUG")
+		_, entries := roundTripWithLog(t)
+
+		response := logEntry(entries, "Received HTTP response")
+		if response == nil {
+			t.Fatalf("expected a response entry, got %v", entries)
+		}
+		expected := map[string]interface{}{
+			"http_method":        "POST",
+			"http_path":          "/rest/api/group?access_token=" + Redacted,
+			"http_attempt":       float64(2),
+			"http_status":        float64(200),
+			"http_response_size": float64(28),
+			"@level":             "debug",
+		}
+		for key, value := range expected {
+			if response[key] != value {
+				t.Errorf("expected %s to be %v, got %v", key, value, response[key])
+			}
+		}
+		if _, ok := response["http_latency_ms"]; !ok {
+			t.Error("expected the latency to be logged")
+		}
+		if logEntry(entries, "Sending HTTP request") != nil || logEntry(entries, "Received HTTP response body") != nil {
+			t.Errorf("expected headers and bodies to be left out below TRACE, got %v", entries)
+		}
+	})
+
+	t.Run("trace", func(t *testing.T) {
+		t.Setenv("TF_LOG_PROVIDER", "DEBUG")
+		t.Setenv("TF_LOG_PROVIDER_CONFLUENCE_HTTP", "TRACE")
+		log, entries := roundTripWithLog(t)
+
+		if logEntry(entries, "Sending HTTP request") == nil || logEntry(entries, "Received HTTP response body") == nil {
+			t.Fatalf("expected headers and bodies to be logged at TRACE, got %v", entries)
+		}
+		for _, secret := range []string{"hunter2", "hunter3", "hunter4", "session=abc"} {
+			if strings.Contains(log, secret) {
+				t.Errorf("expected %q to be masked in the log:\n%s", secret, log)
+			}
+		}
+	})
+}
@@ -0,0 +1,383 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"terraform-provider-confluence/internal/helpers"
+	"terraform-provider-confluence/internal/provider/transferobjects"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &AttachmentResource{}
+var _ resource.ResourceWithImportState = &AttachmentResource{}
+var _ resource.ResourceWithModifyPlan = &AttachmentResource{}
+
+func NewAttachmentResource() resource.Resource {
+	return &AttachmentResource{}
+}
+
+// AttachmentResource defines the resource implementation.
+type AttachmentResource struct {
+	client *helpers.Client
+}
+
+// AttachmentResourceModel describes the resource data model.
+type AttachmentResourceModel struct {
+	PageId      types.String `tfsdk:"page_id"`
+	Title       types.String `tfsdk:"title"`
+	Source      types.String `tfsdk:"source"`
+	Content     types.String `tfsdk:"content"`
+	Comment     types.String `tfsdk:"comment"`
+	Sha256      types.String `tfsdk:"sha256"`
+	MediaType   types.String `tfsdk:"media_type"`
+	Version     types.Int64  `tfsdk:"version"`
+	DownloadUrl types.String `tfsdk:"download_url"`
+	Id          types.String `tfsdk:"id"`
+}
+
+func (r *AttachmentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_attachment"
+}
+
+func (r *AttachmentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Attachment resource",
+
+		Attributes: map[string]schema.Attribute{
+			"page_id": schema.StringAttribute{
+				MarkdownDescription: "The id of the page the file is attached to",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "The file name of the attachment",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source": schema.StringAttribute{
+				MarkdownDescription: "Path to a local file to upload, conflicts with `content`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("content")),
+				},
+			},
+			"content": schema.StringAttribute{
+				MarkdownDescription: "Inline content to upload, conflicts with `source`",
+				Optional:            true,
+			},
+			"comment": schema.StringAttribute{
+				MarkdownDescription: "Comment stored with the uploaded version",
+				Optional:            true,
+			},
+			"sha256": schema.StringAttribute{
+				MarkdownDescription: "SHA-256 of the uploaded data, a new version is uploaded when the local data changes. Unknown until apply when `source` does not exist at plan time",
+				Computed:            true,
+			},
+			"media_type": schema.StringAttribute{
+				MarkdownDescription: "The media type detected by Confluence",
+				Computed:            true,
+			},
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "The current version number of the attachment",
+				Computed:            true,
+			},
+			"download_url": schema.StringAttribute{
+				MarkdownDescription: "The URL to download the attachment",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Attachment identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *AttachmentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*helpers.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan hashes the local data so that a changed file results in a new version
+func (r *AttachmentResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to do on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var data *AttachmentResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() || data.Source.IsUnknown() || data.Content.IsUnknown() {
+		return
+	}
+
+	payload, err := attachmentData(data)
+	if errors.Is(err, fs.ErrNotExist) {
+		// The file may be created during the same apply, it is hashed when it is uploaded
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sha256"), types.StringUnknown())...)
+		if !req.State.Raw.IsNull() {
+			setAttachmentVersionUnknown(ctx, resp)
+		}
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(attachmentDataAttribute(data), "Could not read attachment", err.Error())
+		return
+	}
+	hash := helpers.Sha256String(string(payload))
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sha256"), hash)...)
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+	var state *AttachmentResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if state.Sha256.ValueString() == hash && data.Comment.Equal(state.Comment) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("version"), state.Version)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("download_url"), state.DownloadUrl)...)
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("media_type"), state.MediaType)...)
+		return
+	}
+	setAttachmentVersionUnknown(ctx, resp)
+}
+
+// setAttachmentVersionUnknown plans the attributes that change when a new version is uploaded
+func setAttachmentVersionUnknown(ctx context.Context, resp *resource.ModifyPlanResponse) {
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("version"), types.Int64Unknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("download_url"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("media_type"), types.StringUnknown())...)
+}
+
+func (r *AttachmentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *AttachmentResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload, err := attachmentData(data)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(attachmentDataAttribute(data), "Could not read attachment", err.Error())
+		return
+	}
+
+	// Upload the attachment through API
+	var response transferobjects.AttachmentResults
+	path := fmt.Sprintf("/rest/api/content/%s/child/attachment", data.PageId.ValueString())
+	if err := r.client.PostForm(ctx, path, data.Title.ValueString(), payload, attachmentFormFields(data), &response); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
+		return
+	}
+	if len(response.Results) != 1 {
+		resp.Diagnostics.AddError("Client Error", "Unexpected number of results returned when creating attachment")
+		return
+	}
+
+	data.Id = types.StringValue(response.Results[0].Id)
+	data.Sha256 = types.StringValue(helpers.Sha256String(string(payload)))
+
+	if err := r.refresh(ctx, data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AttachmentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *AttachmentResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	knownVersion := data.Version
+	if err := r.refresh(ctx, data); err != nil {
+		if helpers.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Attachment [%s] no longer exists, removing it from state", data.Id.ValueString()))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
+	// Someone uploaded another version, the data no longer matches the recorded hash
+	if !knownVersion.IsNull() && !knownVersion.Equal(data.Version) {
+		data.Sha256 = types.StringValue("")
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AttachmentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *AttachmentResourceModel
+	var state *AttachmentResourceModel
+
+	// Read Terraform plan and prior state data into the models
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	payload, err := attachmentData(data)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(attachmentDataAttribute(data), "Could not read attachment", err.Error())
+		return
+	}
+	hash := helpers.Sha256String(string(payload))
+	data.Id = state.Id
+
+	// Upload a new version only when the data or its comment changed
+	if hash != state.Sha256.ValueString() || !data.Comment.Equal(state.Comment) {
+		var response transferobjects.Attachment
+		path := fmt.Sprintf("/rest/api/content/%s/child/attachment/%s/data", data.PageId.ValueString(), state.Id.ValueString())
+		if err := r.client.PostForm(ctx, path, data.Title.ValueString(), payload, attachmentFormFields(data), &response); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
+			return
+		}
+	}
+	data.Sha256 = types.StringValue(hash)
+
+	if err := r.refresh(ctx, data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *AttachmentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *AttachmentResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete the attachment through the API
+	path := fmt.Sprintf("/rest/api/content/%s", data.Id.ValueString())
+	if err := r.client.Delete(ctx, path); err != nil {
+		if helpers.IsNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+}
+
+func (r *AttachmentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// refresh loads the attachment metadata from Confluence, the data itself is never downloaded
+func (r *AttachmentResource) refresh(ctx context.Context, data *AttachmentResourceModel) error {
+	var response transferobjects.Attachment
+	path := fmt.Sprintf("/rest/api/content/%s?expand=version,container", data.Id.ValueString())
+	if err := r.client.Get(ctx, path, &response); err != nil {
+		return err
+	}
+
+	data.Title = types.StringValue(response.Title)
+	if response.Container != nil && response.Container.Id != "" {
+		data.PageId = types.StringValue(response.Container.Id)
+	}
+	if response.Version != nil {
+		data.Version = types.Int64Value(int64(response.Version.Number))
+	}
+	if response.Metadata != nil {
+		data.MediaType = types.StringValue(response.Metadata.MediaType)
+	} else {
+		data.MediaType = types.StringNull()
+	}
+	if response.Links != nil && response.Links.Download != "" {
+		data.DownloadUrl = types.StringValue(r.client.URL(response.Links.Context + response.Links.Download))
+	} else {
+		data.DownloadUrl = types.StringNull()
+	}
+	if data.Sha256.IsNull() || data.Sha256.IsUnknown() {
+		// Imported attachments have no known hash, the next apply uploads the configured data
+		data.Sha256 = types.StringValue("")
+	}
+	return nil
+}
+
+// attachmentData returns the bytes to upload, either read from source or taken from content
+func attachmentData(data *AttachmentResourceModel) ([]byte, error) {
+	if !data.Source.IsNull() {
+		return os.ReadFile(data.Source.ValueString())
+	}
+	if !data.Content.IsNull() {
+		return []byte(data.Content.ValueString()), nil
+	}
+	return nil, errors.New("either source or content has to be set")
+}
+
+// attachmentDataAttribute returns the attribute the data to upload is configured in
+func attachmentDataAttribute(data *AttachmentResourceModel) path.Path {
+	if data.Source.IsNull() && !data.Content.IsNull() {
+		return path.Root("content")
+	}
+	return path.Root("source")
+}
+
+func attachmentFormFields(data *AttachmentResourceModel) map[string]string {
+	fields := map[string]string{"minorEdit": "true"}
+	if !data.Comment.IsNull() {
+		fields["comment"] = data.Comment.ValueString()
+	}
+	return fields
+}
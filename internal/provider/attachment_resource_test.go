@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"terraform-provider-confluence/internal/helpers"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestAttachmentData(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "notes.txt")
+	if err := os.WriteFile(source, []byte("from file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	data := &AttachmentResourceModel{
+		Source:  types.StringValue(source),
+		Content: types.StringNull(),
+	}
+	payload, err := attachmentData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(payload) != "from file" {
+		t.Fatalf("expected file content, got %q", payload)
+	}
+
+	data = &AttachmentResourceModel{
+		Source:  types.StringNull(),
+		Content: types.StringValue("inline"),
+	}
+	payload, err = attachmentData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if helpers.Sha256String(string(payload)) != helpers.Sha256String("inline") {
+		t.Fatalf("expected inline content, got %q", payload)
+	}
+
+	data.Source = types.StringValue(filepath.Join(t.TempDir(), "missing.txt"))
+	if _, err := attachmentData(data); err == nil {
+		t.Fatalf("expected an error for a missing source file")
+	}
+}
+
+func TestAttachmentFormFields(t *testing.T) {
+	fields := attachmentFormFields(&AttachmentResourceModel{Comment: types.StringNull()})
+	if _, ok := fields["comment"]; ok {
+		t.Fatalf("expected no comment field, got %v", fields)
+	}
+	fields = attachmentFormFields(&AttachmentResourceModel{Comment: types.StringValue("v2")})
+	if fields["comment"] != "v2" {
+		t.Fatalf("expected the comment to be sent, got %v", fields)
+	}
+}
+
+func TestAttachmentModifyPlan(t *testing.T) {
+	ctx := context.Background()
+	r := &AttachmentResource{}
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	plan := func(source string) tfsdk.Plan {
+		plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		diags := plan.Set(ctx, &AttachmentResourceModel{
+			PageId:      types.StringValue("42"),
+			Title:       types.StringValue("notes.txt"),
+			Source:      types.StringValue(source),
+			Content:     types.StringNull(),
+			Comment:     types.StringNull(),
+			Sha256:      types.StringUnknown(),
+			MediaType:   types.StringUnknown(),
+			Version:     types.Int64Unknown(),
+			DownloadUrl: types.StringUnknown(),
+			Id:          types.StringUnknown(),
+		})
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		return plan
+	}
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+
+	source := filepath.Join(t.TempDir(), "notes.txt")
+	req := fwresource.ModifyPlanRequest{Plan: plan(source), State: state}
+	resp := &fwresource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, resp)
+	var sha256 types.String
+	resp.Plan.GetAttribute(ctx, path.Root("sha256"), &sha256)
+	if resp.Diagnostics.HasError() || !sha256.IsUnknown() {
+		t.Fatalf("expected a file created during apply to leave the hash unknown, got %v %v", sha256, resp.Diagnostics)
+	}
+
+	if err := os.WriteFile(source, []byte("from file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	req = fwresource.ModifyPlanRequest{Plan: plan(source), State: state}
+	resp = &fwresource.ModifyPlanResponse{Plan: req.Plan}
+	r.ModifyPlan(ctx, req, resp)
+	resp.Plan.GetAttribute(ctx, path.Root("sha256"), &sha256)
+	if resp.Diagnostics.HasError() || sha256.ValueString() != helpers.Sha256String("from file") {
+		t.Fatalf("expected the hash of the file, got %v %v", sha256, resp.Diagnostics)
+	}
+}
+
+func TestAttachmentDataAttribute(t *testing.T) {
+	data := &AttachmentResourceModel{Source: types.StringNull(), Content: types.StringValue("inline")}
+	if !attachmentDataAttribute(data).Equal(path.Root("content")) {
+		t.Fatalf("expected content, got %s", attachmentDataAttribute(data))
+	}
+	data = &AttachmentResourceModel{Source: types.StringValue("notes.txt"), Content: types.StringNull()}
+	if !attachmentDataAttribute(data).Equal(path.Root("source")) {
+		t.Fatalf("expected source, got %s", attachmentDataAttribute(data))
+	}
+}
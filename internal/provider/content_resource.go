@@ -0,0 +1,410 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"terraform-provider-confluence/internal/helpers"
+	"terraform-provider-confluence/internal/provider/spacekey"
+	"terraform-provider-confluence/internal/provider/transferobjects"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &ContentResource{}
+var _ resource.ResourceWithImportState = &ContentResource{}
+
+var storageWhitespace = regexp.MustCompile(`>\s+<`)
+
+func NewContentResource() resource.Resource {
+	return &ContentResource{}
+}
+
+// ContentResource defines the resource implementation.
+type ContentResource struct {
+	client *helpers.Client
+}
+
+// ContentResourceModel describes the resource data model.
+type ContentResourceModel struct {
+	Type    types.String `tfsdk:"type"`
+	Space   types.String `tfsdk:"space"`
+	Title   types.String `tfsdk:"title"`
+	Body    types.String `tfsdk:"body"`
+	Parent  types.String `tfsdk:"parent"`
+	Labels  types.Set    `tfsdk:"labels"`
+	Version types.Int64  `tfsdk:"version"`
+	Url     types.String `tfsdk:"url"`
+	Id      types.String `tfsdk:"id"`
+}
+
+func (r *ContentResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_content"
+}
+
+func (r *ContentResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Content resource (pages and blog posts)",
+
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the content, either `page` or `blogpost` (defaults to `page`)",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("page", "blogpost"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringDefault("page"),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"space": schema.StringAttribute{
+				MarkdownDescription: "The key of the space the content belongs to",
+				Required:            true,
+				Validators: []validator.String{
+					spacekey.Validator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					spacekey.RequiresReplace(),
+				},
+			},
+			"title": schema.StringAttribute{
+				MarkdownDescription: "The title of the content",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 255),
+				},
+			},
+			"body": schema.StringAttribute{
+				MarkdownDescription: "The body of the content in storage format. Whitespace differences between tags are ignored when comparing with Confluence",
+				Required:            true,
+			},
+			"parent": schema.StringAttribute{
+				MarkdownDescription: "The id of the parent page, changing it moves the page. Keeps the current parent when not configured",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"labels": schema.SetAttribute{
+				MarkdownDescription: "The global labels of the content",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"version": schema.Int64Attribute{
+				MarkdownDescription: "The current version number of the content",
+				Computed:            true,
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the content",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Content identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ContentResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*helpers.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *ContentResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data *ContentResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	body := contentFromResourceModel(data)
+	var labels []string
+	resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &labels, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if len(labels) > 0 {
+		body.Metadata = &transferobjects.ContentMetadata{Labels: labelsFromNames(labels)}
+	}
+
+	// Create the content through API
+	var response transferobjects.Content
+	if err := r.client.Post(ctx, "/rest/api/content", body, &response, []string{}); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
+		return
+	}
+
+	// Save id into the Terraform state.
+	data.Id = types.StringValue(response.Id)
+
+	if err := r.refresh(ctx, data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ContentResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ContentResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := r.refresh(ctx, data); err != nil {
+		if helpers.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Content [%s] no longer exists, removing it from state", data.Id.ValueString()))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ContentResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var data *ContentResourceModel
+	var state *ContentResourceModel
+
+	// Read Terraform plan and prior state data into the models
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only publish a new version when the content itself changed
+	if !data.Title.Equal(state.Title) ||
+		!data.Parent.Equal(state.Parent) ||
+		normalizeStorageBody(data.Body.ValueString()) != normalizeStorageBody(state.Body.ValueString()) {
+		body := contentFromResourceModel(data)
+		body.Id = state.Id.ValueString()
+		body.Version = &transferobjects.Version{Number: int(state.Version.ValueInt64()) + 1}
+
+		var response transferobjects.Content
+		path := fmt.Sprintf("/rest/api/content/%s", state.Id.ValueString())
+		if err := r.client.Put(ctx, path, body, &response, []string{}); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
+			return
+		}
+	}
+
+	var plannedLabels, currentLabels []string
+	resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &plannedLabels, false)...)
+	resp.Diagnostics.Append(state.Labels.ElementsAs(ctx, &currentLabels, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if err := r.updateLabels(ctx, state.Id.ValueString(), currentLabels, plannedLabels); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
+		return
+	}
+
+	data.Id = state.Id
+	if err := r.refresh(ctx, data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ContentResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *ContentResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Delete the content through the API
+	path := fmt.Sprintf("/rest/api/content/%s", data.Id.ValueString())
+	if err := r.client.Delete(ctx, path); err != nil {
+		if helpers.IsNotFound(err) {
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+}
+
+func (r *ContentResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// refresh loads the content and its labels from Confluence into the model
+func (r *ContentResource) refresh(ctx context.Context, data *ContentResourceModel) error {
+	var response transferobjects.Content
+	path := fmt.Sprintf("/rest/api/content/%s?expand=space,body.storage,version,ancestors", data.Id.ValueString())
+	if err := r.client.Get(ctx, path, &response); err != nil {
+		return err
+	}
+
+	labels, err := helpers.Paginate[transferobjects.Label](r.client, fmt.Sprintf("/rest/api/content/%s/label", data.Id.ValueString())).All(ctx)
+	if err != nil {
+		return err
+	}
+
+	data.Id = types.StringValue(response.Id)
+	data.Type = types.StringValue(response.Type)
+	data.Title = types.StringValue(response.Title)
+	if response.Space != nil {
+		data.Space = spacekey.Refresh(data.Space, response.Space.Key)
+	}
+	if response.Body != nil && response.Body.Storage != nil {
+		// Confluence reformats the storage body, keep ours when only whitespace differs
+		if normalizeStorageBody(response.Body.Storage.Value) != normalizeStorageBody(data.Body.ValueString()) {
+			data.Body = types.StringValue(response.Body.Storage.Value)
+		}
+	}
+	if response.Version != nil {
+		data.Version = types.Int64Value(int64(response.Version.Number))
+	}
+	if len(response.Ancestors) > 0 {
+		data.Parent = types.StringValue(response.Ancestors[len(response.Ancestors)-1].Id)
+	} else {
+		data.Parent = types.StringNull()
+	}
+	if response.Links != nil {
+		data.Url = types.StringValue(r.client.URL(response.Links.Context + response.Links.WebUI))
+	} else {
+		data.Url = types.StringNull()
+	}
+
+	var names []string
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	if len(names) > 0 || !data.Labels.IsNull() {
+		sort.Strings(names)
+		labelSet, diags := types.SetValueFrom(ctx, types.StringType, names)
+		if diags.HasError() {
+			return fmt.Errorf("could not convert labels: %v", diags)
+		}
+		data.Labels = labelSet
+	}
+	return nil
+}
+
+// updateLabels adds and removes labels so that the content ends up with the planned ones
+func (r *ContentResource) updateLabels(ctx context.Context, id string, current []string, planned []string) error {
+	var toAdd []string
+	for _, label := range planned {
+		if !helpers.Contains(current, label) {
+			toAdd = append(toAdd, label)
+		}
+	}
+	if len(toAdd) > 0 {
+		path := fmt.Sprintf("/rest/api/content/%s/label", id)
+		if err := r.client.Post(ctx, path, labelsFromNames(toAdd), nil, nil); err != nil {
+			return err
+		}
+	}
+	for _, label := range current {
+		if helpers.Contains(planned, label) {
+			continue
+		}
+		path := fmt.Sprintf("/rest/api/content/%s/label?name=%s", id, url.QueryEscape(label))
+		if err := r.client.Delete(ctx, path); err != nil && !helpers.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func contentFromResourceModel(data *ContentResourceModel) *transferobjects.Content {
+	content := &transferobjects.Content{
+		Type:  data.Type.ValueString(),
+		Title: data.Title.ValueString(),
+		Space: &transferobjects.SpaceKey{
+			Key: spacekey.Normalize(data.Space.ValueString()),
+		},
+		Body: &transferobjects.Body{
+			Storage: &transferobjects.Storage{
+				Value:          data.Body.ValueString(),
+				Representation: "storage",
+			},
+		},
+	}
+	if content.Type == "" {
+		content.Type = "page"
+	}
+	if !data.Parent.IsNull() && !data.Parent.IsUnknown() && data.Parent.ValueString() != "" {
+		content.Ancestors = []*transferobjects.Content{
+			{
+				Id: data.Parent.ValueString(),
+			},
+		}
+	}
+	return content
+}
+
+func labelsFromNames(names []string) []*transferobjects.Label {
+	labels := make([]*transferobjects.Label, len(names))
+	for i, name := range names {
+		labels[i] = &transferobjects.Label{
+			Prefix: "global",
+			Name:   name,
+		}
+	}
+	return labels
+}
+
+// normalizeStorageBody collapses whitespace so that formatting changes do not count as a difference
+func normalizeStorageBody(body string) string {
+	body = strings.Join(strings.Fields(body), " ")
+	return storageWhitespace.ReplaceAllString(body, "><")
+}
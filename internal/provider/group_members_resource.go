@@ -0,0 +1,304 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"terraform-provider-confluence/internal/helpers"
+	"terraform-provider-confluence/internal/provider/transferobjects"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ resource.Resource = &GroupMembersResource{}
+var _ resource.ResourceWithImportState = &GroupMembersResource{}
+
+func NewGroupMembersResource() resource.Resource {
+	return &GroupMembersResource{}
+}
+
+// GroupMembersResource defines the resource implementation.
+type GroupMembersResource struct {
+	client *helpers.Client
+}
+
+// GroupMembersResourceModel describes the resource data model.
+type GroupMembersResourceModel struct {
+	GroupId    types.String `tfsdk:"group_id"`
+	AccountIds types.Set    `tfsdk:"account_ids"`
+	Id         types.String `tfsdk:"id"`
+}
+
+func (r *GroupMembersResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group_members"
+}
+
+func (r *GroupMembersResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Authoritative group members resource. Members of the group that are not listed are removed, " +
+			"do not combine it with `confluence_group_membership` for the same group. " +
+			"Destroying the resource only removes the listed members, members added outside of Terraform since the last apply stay in the group",
+
+		Attributes: map[string]schema.Attribute{
+			"group_id": schema.StringAttribute{
+				MarkdownDescription: "The group id whose members are managed",
+				Required:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"account_ids": schema.SetAttribute{
+				MarkdownDescription: "The account ids of all members of the group",
+				ElementType:         types.StringType,
+				Required:            true,
+			},
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Resource identifier",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *GroupMembersResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*helpers.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *GroupMembersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
+	var data *GroupMembersResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(data.GroupId.ValueString())
+	r.reconcile(ctx, data, &resp.State, &resp.Diagnostics)
+}
+
+func (r *GroupMembersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
+	var data *GroupMembersResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get the members through the API
+	current, err := r.currentMembers(ctx, data.GroupId.ValueString())
+	if err != nil {
+		if helpers.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Group [%s] no longer exists, removing members from state", data.GroupId.ValueString()))
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
+	data.Id = types.StringValue(data.GroupId.ValueString())
+	resp.Diagnostics.Append(setGroupMembers(ctx, data, current)...)
+
+	// Save updated data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *GroupMembersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
+	var data *GroupMembersResourceModel
+
+	// Read Terraform plan data into the model
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.reconcile(ctx, data, &resp.State, &resp.Diagnostics)
+}
+
+func (r *GroupMembersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data *GroupMembersResourceModel
+	var accountIds []string
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(data.AccountIds.ElementsAs(ctx, &accountIds, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Remove the members through the API, a failed member does not stop the others
+	var remaining []string
+	for _, accountId := range accountIds {
+		if err := removeGroupMember(ctx, r.client, data.GroupId.ValueString(), accountId); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error while removing member [%s], got error: %s", accountId, err))
+			remaining = append(remaining, accountId)
+		}
+	}
+
+	// Keep the members that could not be removed, so that only those are retried
+	if len(remaining) > 0 {
+		resp.Diagnostics.Append(setGroupMembers(ctx, data, remaining)...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	}
+}
+
+func (r *GroupMembersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("group_id"), req, resp)
+}
+
+// reconcile adds and removes only the members that differ between the group and the plan.
+// The actual members are saved into the state even when a request fails halfway.
+func (r *GroupMembersResource) reconcile(ctx context.Context, data *GroupMembersResourceModel, state *tfsdk.State, diags *diag.Diagnostics) {
+	var planned []string
+	diags.Append(data.AccountIds.ElementsAs(ctx, &planned, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	groupId := data.GroupId.ValueString()
+	current, err := r.currentMembers(ctx, groupId)
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
+	toAdd, toRemove := diffGroupMembers(current, planned)
+	tflog.Debug(ctx, fmt.Sprintf("Group [%s] members to add: %v, to remove: %v", groupId, toAdd, toRemove))
+
+	for _, accountId := range toAdd {
+		if err = addGroupMember(ctx, r.client, groupId, accountId); err != nil {
+			break
+		}
+		current = append(current, accountId)
+	}
+	var removed []string
+	if err == nil {
+		for _, accountId := range toRemove {
+			if err = removeGroupMember(ctx, r.client, groupId, accountId); err != nil {
+				break
+			}
+			removed = append(removed, accountId)
+		}
+	}
+	if err != nil {
+		diags.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		var remaining []string
+		for _, accountId := range current {
+			if !helpers.Contains(removed, accountId) {
+				remaining = append(remaining, accountId)
+			}
+		}
+		diags.Append(setGroupMembers(ctx, data, remaining)...)
+	}
+
+	// Save data into Terraform state
+	diags.Append(state.Set(ctx, &data)...)
+}
+
+// currentMembers returns the account ids of all members of the group
+func (r *GroupMembersResource) currentMembers(ctx context.Context, groupId string) ([]string, error) {
+	members, err := listGroupMembers(ctx, r.client, groupId)
+	if err != nil {
+		return nil, err
+	}
+	accountIds := make([]string, 0, len(members))
+	for _, member := range members {
+		accountIds = append(accountIds, member.AccountID)
+	}
+	return accountIds, nil
+}
+
+func setGroupMembers(ctx context.Context, data *GroupMembersResourceModel, accountIds []string) diag.Diagnostics {
+	sorted := append([]string{}, accountIds...)
+	sort.Strings(sorted)
+	accountIdSet, diags := types.SetValueFrom(ctx, types.StringType, sorted)
+	if diags.HasError() {
+		return diags
+	}
+	data.AccountIds = accountIdSet
+	return diags
+}
+
+// diffGroupMembers returns the account ids that have to be added to and removed from the group
+func diffGroupMembers(current []string, planned []string) ([]string, []string) {
+	var toAdd, toRemove []string
+	for _, accountId := range planned {
+		if !helpers.Contains(current, accountId) && !helpers.Contains(toAdd, accountId) {
+			toAdd = append(toAdd, accountId)
+		}
+	}
+	for _, accountId := range current {
+		if !helpers.Contains(planned, accountId) && !helpers.Contains(toRemove, accountId) {
+			toRemove = append(toRemove, accountId)
+		}
+	}
+	return toAdd, toRemove
+}
+
+func addGroupMember(ctx context.Context, client *helpers.Client, groupId string, accountId string) error {
+	path := fmt.Sprintf("/rest/api/group/userByGroupId?groupId=%s", groupId)
+	return client.Post(ctx, path, transferobjects.AccountIDRecord{AccountID: accountId}, nil, nil)
+}
+
+func removeGroupMember(ctx context.Context, client *helpers.Client, groupId string, accountId string) error {
+	path := fmt.Sprintf("/rest/api/group/userByGroupId?groupId=%s&accountId=%s", groupId, accountId)
+	if err := client.Delete(ctx, path); err != nil && !helpers.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
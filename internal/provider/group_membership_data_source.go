@@ -87,6 +87,10 @@ func (d *GroupMembershipDataSource) Configure(ctx context.Context, req datasourc
 }
 
 func (d *GroupMembershipDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	if !requireFeature(d.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
 	var data GroupMembershipDataSourceModel
 
 	// Read Terraform configuration data into the model
@@ -108,7 +112,7 @@ func (d *GroupMembershipDataSource) Read(ctx context.Context, req datasource.Rea
 	if !data.GroupName.IsNull() && data.GroupId.IsNull() {
 		var response transferobjects.Group
 		path := fmt.Sprintf("/rest/api/group/by-name?name=%s", data.GroupName.ValueString())
-		if err := d.client.Get(path, &response); err != nil {
+		if err := d.client.Get(ctx, path, &response); err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
 			return
 		}
@@ -116,7 +120,7 @@ func (d *GroupMembershipDataSource) Read(ctx context.Context, req datasource.Rea
 	}
 
 	// Get the privileges through the API
-	var elements, err = getMembersWithPagination(d, data.GroupId.ValueString())
+	var elements, err = getMembersWithPagination(ctx, d, data.GroupId.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
 		return
@@ -134,32 +138,27 @@ func (d *GroupMembershipDataSource) Read(ctx context.Context, req datasource.Rea
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func getMembersWithPagination(d *GroupMembershipDataSource, groupId string) (map[string]attr.Value, error) {
-	limit := 200
-	size := 999
+func getMembersWithPagination(ctx context.Context, d *GroupMembershipDataSource, groupId string) (map[string]attr.Value, error) {
 	var elements = make(map[string]attr.Value)
-	totalSize := 0
-
-	// while we return the max amount of records
-	for size >= limit {
-		offset := len(elements)
-		var response transferobjects.GroupMembersResponse
-		path := fmt.Sprintf("/rest/api/group/%s/membersByGroupId?limit=%d&start=%d&shouldReturnTotalSize=true", groupId, limit, offset)
-		if err := d.client.Get(path, &response); err != nil {
-			return make(map[string]attr.Value), err
-		}
+	members, err := listGroupMembers(ctx, d.client, groupId)
+	for _, member := range members {
+		elements[member.Email] = types.StringValue(member.AccountID)
+	}
+	return elements, err
+}
 
-		for _, member := range response.Members {
-			elements[member.Email] = types.StringValue(member.AccountID)
-		}
-		size = response.Size
-		limit = response.Limit
-		totalSize = response.TotalSize
+// listGroupMembers pages through all members of the group
+func listGroupMembers(ctx context.Context, client *helpers.Client, groupId string) ([]transferobjects.Member, error) {
+	path := fmt.Sprintf("/rest/api/group/%s/membersByGroupId?shouldReturnTotalSize=true", groupId)
+	pages := helpers.Paginate[transferobjects.Member](client, path)
+	members, err := pages.All(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	if totalSize != len(elements) {
-		return elements, errors.New("consistency could not be guaranteed - expected members != actual members")
+	if pages.TotalSize() != len(members) {
+		return members, errors.New("consistency could not be guaranteed - expected members != actual members")
 	}
 
-	return elements, nil
+	return members, nil
 }
@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"terraform-provider-confluence/internal/helpers"
 	"terraform-provider-confluence/internal/provider/transferobjects"
 
@@ -12,11 +13,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ resource.Resource = &GroupMembershipResource{}
 var _ resource.ResourceWithImportState = &GroupMembershipResource{}
+var _ resource.ResourceWithUpgradeState = &GroupMembershipResource{}
 
 func NewGroupMembershipResource() resource.Resource {
 	return &GroupMembershipResource{}
@@ -42,6 +45,8 @@ func (r *GroupMembershipResource) Schema(ctx context.Context, req resource.Schem
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Group membership resource",
+		// Version 1 separates the group id and the account id in the resource identifier
+		Version: 1,
 
 		Attributes: map[string]schema.Attribute{
 			"account_id": schema.StringAttribute{
@@ -60,7 +65,7 @@ func (r *GroupMembershipResource) Schema(ctx context.Context, req resource.Schem
 			},
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Resource identifier",
+				MarkdownDescription: "Resource identifier in the format `GROUPID/ACCOUNTID`",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -90,6 +95,10 @@ func (r *GroupMembershipResource) Configure(ctx context.Context, req resource.Co
 }
 
 func (r *GroupMembershipResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
 	var data *GroupMembershipResourceModel
 	var body transferobjects.AccountIDRecord
 	var itemsToRemove []string
@@ -104,20 +113,23 @@ func (r *GroupMembershipResource) Create(ctx context.Context, req resource.Creat
 
 	// Create the rule through API
 	path := fmt.Sprintf("/rest/api/group/userByGroupId?groupId=%s", data.GroupId.ValueString())
-	if err := r.client.Post(path, body, nil, itemsToRemove); err != nil {
+	if err := r.client.Post(ctx, path, body, nil, itemsToRemove); err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
 		return
 	}
 
 	// Save id into the Terraform state.
-	resourceId := fmt.Sprintf("%s%s", data.GroupId.ValueString(), data.AccountId.ValueString())
-	data.Id = types.StringValue(resourceId)
+	data.Id = types.StringValue(groupMembershipId(data.GroupId.ValueString(), data.AccountId.ValueString()))
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *GroupMembershipResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
 	var data *GroupMembershipResourceModel
 
 	// Read Terraform prior state data into the model
@@ -128,15 +140,23 @@ func (r *GroupMembershipResource) Read(ctx context.Context, req resource.ReadReq
 	}
 
 	// Get the rule through the API
-	var response transferobjects.GroupMembersResponse
-	path := fmt.Sprintf("/rest/api/group/%s/membersByGroupId", data.GroupId.ValueString())
-	if err := r.client.Get(path, &response); err != nil {
+	isMember, err := isGroupMember(ctx, r.client, data.GroupId.ValueString(), data.AccountId.ValueString())
+	if err != nil {
+		if helpers.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Group [%s] no longer exists, removing membership from state", data.GroupId.ValueString()))
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
 		return
 	}
+	if !isMember {
+		tflog.Warn(ctx, fmt.Sprintf("Account [%s] is no longer a member of group [%s], removing membership from state", data.AccountId.ValueString(), data.GroupId.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
-	resourceId := fmt.Sprintf("%s%s", data.GroupId.ValueString(), data.AccountId.ValueString())
-	data.Id = types.StringValue(resourceId)
+	data.Id = types.StringValue(groupMembershipId(data.GroupId.ValueString(), data.AccountId.ValueString()))
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -167,12 +187,82 @@ func (r *GroupMembershipResource) Delete(ctx context.Context, req resource.Delet
 
 	// Get the rule through the API
 	path := fmt.Sprintf("/rest/api/group/userByGroupId?groupId=%s&accountId=%s", data.GroupId.ValueString(), data.AccountId.ValueString())
-	if err := r.client.Delete(path); err != nil {
+	if err := r.client.Delete(ctx, path); err != nil {
+		if helpers.IsNotFound(err) {
+			return
+		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
 		return
 	}
 }
 
 func (r *GroupMembershipResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
+	groupId, accountId, ok := parseGroupMembershipId(req.ID)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier in the format GROUPID/ACCOUNTID, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("group_id"), groupId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("account_id"), accountId)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *GroupMembershipResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 concatenated the group id and the account id without a separator
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"account_id": schema.StringAttribute{Required: true},
+					"group_id":   schema.StringAttribute{Required: true},
+					"id":         schema.StringAttribute{Computed: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var data GroupMembershipResourceModel
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				data.Id = types.StringValue(groupMembershipId(data.GroupId.ValueString(), data.AccountId.ValueString()))
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			},
+		},
+	}
+}
+
+// groupMembershipId joins the group id and the account id, neither of them contains a slash
+func groupMembershipId(groupId string, accountId string) string {
+	return groupId + "/" + accountId
+}
+
+// parseGroupMembershipId splits an identifier built by groupMembershipId
+func parseGroupMembershipId(id string) (string, string, bool) {
+	groupId, accountId, found := strings.Cut(id, "/")
+	if !found || groupId == "" || accountId == "" || strings.Contains(accountId, "/") {
+		return "", "", false
+	}
+	return groupId, accountId, true
+}
+
+// isGroupMember pages through the members of the group until the account is found
+func isGroupMember(ctx context.Context, client *helpers.Client, groupId string, accountId string) (bool, error) {
+	members := helpers.Paginate[transferobjects.Member](client, fmt.Sprintf("/rest/api/group/%s/membersByGroupId", groupId))
+	for members.Next(ctx) {
+		if members.Value().AccountID == accountId {
+			return true, nil
+		}
+	}
+	return false, members.Err()
 }
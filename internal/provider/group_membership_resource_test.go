@@ -60,7 +60,7 @@ func TestAccGroupMembershipResource(t *testing.T) {
 
 	svr.SetSplice("/rest/api/group", func(a string, b []byte) (string, map[string]interface{}) {
 		resource := generateTestGroupMembershipResponseResource()
-		resourceId := fmt.Sprintf("%s%s", resource.GroupId, resource.AccountID)
+		resourceId := groupMembershipId(resource.GroupId, resource.AccountID)
 		var obj map[string]interface{}
 		obj = structs.Map(generateTestGroupMembershipResponseResource())
 		return resourceId, obj
@@ -106,6 +106,23 @@ func TestAccGroupMembershipResource(t *testing.T) {
 	svr.Shutdown()
 }
 
+func TestGroupMembershipId(t *testing.T) {
+	id := groupMembershipId("3fa85f64-5717-4562-b3fc-2c963f66afa6", "5b10a2844c20165700ede21g")
+	if id != "3fa85f64-5717-4562-b3fc-2c963f66afa6/5b10a2844c20165700ede21g" {
+		t.Fatalf("unexpected id %s", id)
+	}
+	groupId, accountId, ok := parseGroupMembershipId(id)
+	if !ok || groupId != "3fa85f64-5717-4562-b3fc-2c963f66afa6" || accountId != "5b10a2844c20165700ede21g" {
+		t.Fatalf("unexpected parse result: %s %s %t", groupId, accountId, ok)
+	}
+
+	for _, id := range []string{"", "3fa85f64", "3fa85f64/", "/5b10a284", "3fa85f64/5b10a284/extra"} {
+		if _, _, ok := parseGroupMembershipId(id); ok {
+			t.Fatalf("expected %q to be rejected", id)
+		}
+	}
+}
+
 func testAccGroupMembershipResourceConfig(item AccountIDRecordTestItem, name string) string {
 	return fmt.Sprintf(`%s
 resource "confluence_group_membership" "%s" {
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
@@ -30,6 +31,8 @@ type GroupResource struct {
 // GroupResourceModel describes the resource data model.
 type GroupResourceModel struct {
 	Name types.String `tfsdk:"name"`
+	Type types.String `tfsdk:"type"`
+	Url  types.String `tfsdk:"url"`
 	Id   types.String `tfsdk:"id"`
 }
 
@@ -44,12 +47,23 @@ func (r *GroupResource) Schema(ctx context.Context, req resource.SchemaRequest,
 
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
-				MarkdownDescription: "The name of the group",
+				MarkdownDescription: "The name of the group. Changing it replaces the group, as Confluence offers no endpoint to rename groups",
 				Required:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the group",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the group",
+				Computed:            true,
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Group identifier",
@@ -82,6 +96,10 @@ func (r *GroupResource) Configure(ctx context.Context, req resource.ConfigureReq
 }
 
 func (r *GroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
 	var data *GroupResourceModel
 	var body transferobjects.Group
 	var itemsToRemove []string
@@ -96,19 +114,24 @@ func (r *GroupResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	// Create the rule through API
 	var response transferobjects.Group
-	if err := r.client.Post("/rest/api/group", body, &response, itemsToRemove); err != nil {
+	if err := r.client.Post(ctx, "/rest/api/group", body, &response, itemsToRemove); err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
 		return
 	}
 
 	// Save id into the Terraform state.
 	data.Id = types.StringValue(response.Id)
+	setGroupAttributes(r.client, data, &response)
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *GroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
 	var data *GroupResourceModel
 
 	// Read Terraform prior state data into the model
@@ -121,10 +144,16 @@ func (r *GroupResource) Read(ctx context.Context, req resource.ReadRequest, resp
 	// Get the rule through the API
 	var response transferobjects.Group
 	path := fmt.Sprintf("/rest/api/group/by-id?id=%s", data.Id.ValueString())
-	if err := r.client.Get(path, &response); err != nil {
+	if err := r.client.Get(ctx, path, &response); err != nil {
+		if helpers.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Group [%s] no longer exists, removing it from state", data.Id.ValueString()))
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
 		return
 	}
+	setGroupAttributes(r.client, data, &response)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -136,8 +165,11 @@ func (r *GroupResource) Update(ctx context.Context, req resource.UpdateRequest,
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
 
-	// Update not supported
-	resp.Diagnostics.AddError("Schema Error", fmt.Sprintf("UPDATE operation not supported"))
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every configurable attribute requires replacement, so there is nothing to send
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -155,12 +187,32 @@ func (r *GroupResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	// Get the rule through the API
 	path := fmt.Sprintf("/rest/api/group/by-id?id=%s", data.Id.ValueString())
-	if err := r.client.Delete(path); err != nil {
+	if err := r.client.Delete(ctx, path); err != nil {
+		if helpers.IsNotFound(err) {
+			return
+		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
 		return
 	}
 }
 
 func (r *GroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !requireFeature(r.client, helpers.FeatureGroupIds, &resp.Diagnostics) {
+		return
+	}
+
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// setGroupAttributes copies the attributes Confluence reports for a group into the model
+func setGroupAttributes(client *helpers.Client, data *GroupResourceModel, group *transferobjects.Group) {
+	if group.Name != "" {
+		data.Name = types.StringValue(group.Name)
+	}
+	data.Type = types.StringValue(group.Type)
+	if group.Links != nil && group.Links.WebUI != "" {
+		data.Url = types.StringValue(client.URL(group.Links.Context + group.Links.WebUI))
+	} else {
+		data.Url = types.StringNull()
+	}
+}
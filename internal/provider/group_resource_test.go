@@ -3,9 +3,11 @@ package provider
 import (
 	"fmt"
 	"github.com/fatih/structs"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"os"
 	"terraform-provider-confluence/internal/fakeserver"
+	"terraform-provider-confluence/internal/helpers"
 	"terraform-provider-confluence/internal/provider/transferobjects"
 	"testing"
 )
@@ -99,3 +101,29 @@ resource "confluence_group" "%s" {
 }
 `, providerConfig, name, group.Name)
 }
+
+func TestSetGroupAttributes(t *testing.T) {
+	client := helpers.NewClient(&helpers.NewClientInput{Site: "example.atlassian.net", PublicSite: "example.atlassian.net", PublicSiteUseTLS: true})
+	data := &GroupResourceModel{Name: types.StringValue("old-name"), Id: types.StringValue("testId")}
+
+	setGroupAttributes(client, data, &transferobjects.Group{
+		Id:    "testId",
+		Name:  "renamed",
+		Type:  "group",
+		Links: &transferobjects.GroupLinks{Context: "/wiki", WebUI: "/people/team/testId"},
+	})
+	if data.Name.ValueString() != "renamed" {
+		t.Errorf("expected the name to be refreshed, got: %s", data.Name)
+	}
+	if data.Type.ValueString() != "group" {
+		t.Errorf("unexpected type: %s", data.Type)
+	}
+	if data.Url.ValueString() != "https://example.atlassian.net/wiki/people/team/testId" {
+		t.Errorf("unexpected url: %s", data.Url)
+	}
+
+	setGroupAttributes(client, data, &transferobjects.Group{Id: "testId", Type: "group"})
+	if data.Name.ValueString() != "renamed" || !data.Url.IsNull() {
+		t.Errorf("expected the name to be kept and the url to be cleared, got: %+v", data)
+	}
+}
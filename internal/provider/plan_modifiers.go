@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// stringDefault returns a plan modifier that sets an unconfigured string attribute to a default value.
+// The attribute has to be Optional and Computed.
+func stringDefault(value string) planmodifier.String {
+	return stringDefaultModifier{value: value}
+}
+
+type stringDefaultModifier struct {
+	value string
+}
+
+func (m stringDefaultModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("Defaults to %q when not configured.", m.value)
+}
+
+func (m stringDefaultModifier) MarkdownDescription(_ context.Context) string {
+	return fmt.Sprintf("Defaults to `%s` when not configured.", m.value)
+}
+
+func (m stringDefaultModifier) PlanModifyString(_ context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+	resp.PlanValue = types.StringValue(m.value)
+}
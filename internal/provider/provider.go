@@ -2,13 +2,27 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
 	"strings"
 	"terraform-provider-confluence/internal/helpers"
+	"time"
 )
 
 // Ensure ConfluenceProvider satisfies various provider interfaces.
@@ -32,6 +46,37 @@ type ConfluenceProviderModel struct {
 
 	Username types.String `tfsdk:"user"`
 	Token    types.String `tfsdk:"token"`
+
+	MaxRetries   types.Int64 `tfsdk:"max_retries"`
+	MaxRetryWait types.Int64 `tfsdk:"max_retry_wait"`
+
+	CredentialsFile types.String `tfsdk:"credentials_file"`
+	Profile         types.String `tfsdk:"profile"`
+
+	Debug types.Bool `tfsdk:"debug"`
+
+	CABundle           types.String `tfsdk:"ca_bundle"`
+	ClientCertificate  types.String `tfsdk:"client_certificate"`
+	ClientKey          types.String `tfsdk:"client_key"`
+	InsecureSkipVerify types.Bool   `tfsdk:"insecure_skip_verify"`
+	ProxyURL           types.String `tfsdk:"proxy_url"`
+	Timeout            types.Int64  `tfsdk:"timeout"`
+
+	APIVersion     types.String `tfsdk:"api_version"`
+	DeploymentType types.String `tfsdk:"deployment_type"`
+
+	Auth *ConfluenceProviderAuthModel `tfsdk:"auth"`
+}
+
+// ConfluenceProviderAuthModel describes the auth block of the provider.
+type ConfluenceProviderAuthModel struct {
+	Type         types.String `tfsdk:"type"`
+	User         types.String `tfsdk:"user"`
+	Token        types.String `tfsdk:"token"`
+	ClientId     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	TokenURL     types.String `tfsdk:"token_url"`
+	CloudId      types.String `tfsdk:"cloud_id"`
 }
 
 func (p *ConfluenceProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -41,37 +86,141 @@ func (p *ConfluenceProvider) Metadata(ctx context.Context, req provider.Metadata
 
 func (p *ConfluenceProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		MarkdownDescription: "Every attribute falls back to its `CONFLUENCE_*` environment variable and then to the selected profile of the credentials file when it is not set.",
 		Attributes: map[string]schema.Attribute{
 			"site": schema.StringAttribute{
-				MarkdownDescription: "Confluence hostname (<name>.atlassian.net if using Cloud Confluence, otherwise hostname)",
-				Required:            true,
+				MarkdownDescription: "Confluence hostname (<name>.atlassian.net if using Cloud Confluence, otherwise hostname). Can also be set with `CONFLUENCE_SITE`",
+				Optional:            true,
 			},
 			"site_tls": schema.BoolAttribute{
-				MarkdownDescription: "Use https for API calls",
+				MarkdownDescription: "Use https for API calls. Can also be set with `CONFLUENCE_SITE_TLS`",
 				Optional:            true,
 			},
 			"public_site": schema.StringAttribute{
-				MarkdownDescription: "Optional public Confluence Server hostname if different than API hostname",
+				MarkdownDescription: "Optional public Confluence Server hostname if different than API hostname. Can also be set with `CONFLUENCE_PUBLIC_SITE`",
 				Optional:            true,
 			},
 			"public_site_tls": schema.BoolAttribute{
-				MarkdownDescription: "Use https for public site URLs",
+				MarkdownDescription: "Use https for public site URLs. Can also be set with `CONFLUENCE_PUBLIC_SITE_TLS`",
 				Optional:            true,
 			},
 			"context": schema.StringAttribute{
-				MarkdownDescription: "Confluence path context (Will default to /wiki if using an atlassian.net hostname)",
+				MarkdownDescription: "Confluence path context (Will default to /wiki if using an atlassian.net hostname). Can also be set with `CONFLUENCE_CONTEXT`",
 				Optional:            true,
 			},
 			"user": schema.StringAttribute{
-				MarkdownDescription: "User's email address for Cloud Confluence or username for Confluence Server",
-				Required:            true,
+				MarkdownDescription: "User's email address for Cloud Confluence or username for Confluence Server. Can also be set with `CONFLUENCE_USER`",
+				Optional:            true,
 				Sensitive:           true,
 			},
 			"token": schema.StringAttribute{
-				MarkdownDescription: "Confluence API Token for Cloud Confluence or password for Confluence Server/Cloud",
-				Required:            true,
+				MarkdownDescription: "Confluence API Token for Cloud Confluence or password for Confluence Server/Cloud. Can also be set with `CONFLUENCE_TOKEN`",
+				Optional:            true,
 				Sensitive:           true,
 			},
+			"max_retries": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of times a request is retried after a 429 or 5xx response (defaults to 4, 0 disables retries). Can also be set with `CONFLUENCE_MAX_RETRIES`",
+				Optional:            true,
+			},
+			"max_retry_wait": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of seconds to wait between two attempts, also caps Retry-After hints (defaults to 30). Can also be set with `CONFLUENCE_MAX_RETRY_WAIT`",
+				Optional:            true,
+			},
+			"credentials_file": schema.StringAttribute{
+				MarkdownDescription: "Path of an INI style credentials file with one section per profile (defaults to `" + helpers.DefaultCredentialsFile + "` when it exists). Can also be set with `CONFLUENCE_CREDENTIALS_FILE`",
+				Optional:            true,
+			},
+			"profile": schema.StringAttribute{
+				MarkdownDescription: "Profile of the credentials file to use (defaults to `" + helpers.DefaultProfile + "`). Can also be set with `CONFLUENCE_PROFILE`",
+				Optional:            true,
+			},
+			"debug": schema.BoolAttribute{
+				MarkdownDescription: "Include request bodies in the errors of failed requests, with passwords, tokens and secrets scrubbed. Can also be set with `CONFLUENCE_DEBUG`",
+				Optional:            true,
+			},
+			"ca_bundle": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates, or the path of a file containing them, trusted in addition to the system certificates. Can also be set with `CONFLUENCE_CA_BUNDLE`",
+				Optional:            true,
+			},
+			"client_certificate": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded client certificate, or the path of a file containing it, for mutual TLS. Requires `client_key`. Can also be set with `CONFLUENCE_CLIENT_CERTIFICATE`",
+				Optional:            true,
+			},
+			"client_key": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded private key of the client certificate, or the path of a file containing it. Can also be set with `CONFLUENCE_CLIENT_KEY`",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"insecure_skip_verify": schema.BoolAttribute{
+				MarkdownDescription: "Skip the verification of the server certificate. Only meant for test instances. Can also be set with `CONFLUENCE_INSECURE_SKIP_VERIFY`",
+				Optional:            true,
+			},
+			"proxy_url": schema.StringAttribute{
+				MarkdownDescription: "URL of the proxy all requests are sent through, e.g. `http://proxy.example.com:3128` (defaults to the `HTTPS_PROXY`, `HTTP_PROXY` and `NO_PROXY` environment variables). Can also be set with `CONFLUENCE_PROXY_URL`",
+				Optional:            true,
+			},
+			"timeout": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of seconds a single request may take (defaults to 10). Can also be set with `CONFLUENCE_TIMEOUT`",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"api_version": schema.StringAttribute{
+				MarkdownDescription: "REST API version spaces and space permissions are read through, `v1` or `v2` (defaults to `v2` on Confluence Cloud and `v1` otherwise). Can also be set with `CONFLUENCE_API_VERSION`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(helpers.APIVersions...),
+				},
+			},
+			"deployment_type": schema.StringAttribute{
+				MarkdownDescription: "Either `cloud` or `datacenter`, overrides the deployment type detected by probing the server. Can also be set with `CONFLUENCE_DEPLOYMENT_TYPE`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(helpers.DeploymentTypes...),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"auth": schema.SingleNestedBlock{
+				MarkdownDescription: "Selects how the provider authenticates. Without this block `user` and `token` are sent as basic authentication",
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						MarkdownDescription: "One of `basic` (user and API token or password), `bearer` (Data Center personal access token), `oauth` (OAuth 2.0 access token) or `client_credentials` (OAuth 2.0 service account). Can also be set with `CONFLUENCE_AUTH_TYPE`",
+						Optional:            true,
+						Validators: []validator.String{
+							stringvalidator.OneOf(helpers.AuthTypes...),
+						},
+					},
+					"user": schema.StringAttribute{
+						MarkdownDescription: "User for basic authentication, overrides the top level `user`",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"token": schema.StringAttribute{
+						MarkdownDescription: "API token or password for basic authentication, the personal access token or the OAuth 2.0 access token. Overrides the top level `token`",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"client_id": schema.StringAttribute{
+						MarkdownDescription: "OAuth 2.0 client id of the service account. Can also be set with `CONFLUENCE_AUTH_CLIENT_ID`",
+						Optional:            true,
+					},
+					"client_secret": schema.StringAttribute{
+						MarkdownDescription: "OAuth 2.0 client secret of the service account. Can also be set with `CONFLUENCE_AUTH_CLIENT_SECRET`",
+						Optional:            true,
+						Sensitive:           true,
+					},
+					"token_url": schema.StringAttribute{
+						MarkdownDescription: "Endpoint access tokens are fetched from (defaults to `" + helpers.DefaultTokenURL + "`). Can also be set with `CONFLUENCE_AUTH_TOKEN_URL`",
+						Optional:            true,
+					},
+					"cloud_id": schema.StringAttribute{
+						MarkdownDescription: "Cloud id of the site, OAuth 2.0 requests are routed through `" + helpers.CloudGatewayHost + "/ex/confluence/{cloud_id}`. Looked up from the site when not set. Can also be set with `CONFLUENCE_AUTH_CLOUD_ID`",
+						Optional:            true,
+					},
+				},
+			},
 		},
 	}
 }
@@ -85,63 +234,308 @@ func (p *ConfluenceProvider) Configure(ctx context.Context, req provider.Configu
 		return
 	}
 
-	// Configuration values are now available.
-	// if data.Endpoint.IsNull() { /* ... */ }
+	input, diags := clientInputFromProviderModel(ctx, &data, os.Getenv, helpers.DiscoverCloudId)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	site := ""
-	siteTls := true
-	publicSite := ""
-	publicSiteTls := true
-	context := ""
-	username := "user"
-	password := "password"
+	// Client configuration for data sources and resources
+	client := helpers.NewClient(input)
 
-	if !data.Site.IsNull() {
-		site = data.Site.ValueString()
-		if strings.HasSuffix(site, ".atlassian.net") && data.Context.IsNull() {
-			context = "/wiki"
+	if err := client.DetectCapabilities(ctx); err != nil {
+		resp.Diagnostics.AddWarning(
+			"Unable to Detect Confluence Deployment",
+			fmt.Sprintf("Assuming %s based on the site name. Set deployment_type if this is wrong, Cloud sites on a custom domain also need the context /wiki: %s", client.Capabilities(), err),
+		)
+	}
+	tflog.Info(ctx, "Configured Confluence client", map[string]interface{}{
+		"confluence_deployment_type": client.Capabilities().DeploymentType,
+		"confluence_version":         client.Capabilities().Version,
+		"confluence_build_number":    client.Capabilities().BuildNumber,
+	})
+
+	resp.DataSourceData = client
+	resp.ResourceData = client
+}
+
+// providerSettings resolves every provider attribute from the configuration, the environment and the
+// credentials file, in that order
+type providerSettings struct {
+	data    *ConfluenceProviderModel
+	getenv  func(string) string
+	profile map[string]string
+	diags   diag.Diagnostics
+}
+
+func (s *providerSettings) unknown(attribute string, value attr.Value) bool {
+	if !value.IsUnknown() {
+		return false
+	}
+	s.diags.AddAttributeError(
+		attributePath(attribute),
+		"Unknown Confluence Provider Configuration",
+		fmt.Sprintf("The provider cannot be configured with an unknown value for %s. "+
+			"Either set it statically in the configuration or use the %s environment variable.", attribute, envName(attribute)),
+	)
+	return true
+}
+
+func (s *providerSettings) lookup(attribute string) (string, bool) {
+	if value := s.getenv(envName(attribute)); value != "" {
+		return value, true
+	}
+	value, ok := s.profile[attribute]
+	return value, ok && value != ""
+}
+
+func (s *providerSettings) string(attribute string, value types.String) string {
+	if s.unknown(attribute, value) {
+		return ""
+	}
+	if !value.IsNull() {
+		return value.ValueString()
+	}
+	result, _ := s.lookup(attribute)
+	return result
+}
+
+func (s *providerSettings) bool(attribute string, value types.Bool, fallback bool) bool {
+	if s.unknown(attribute, value) {
+		return fallback
+	}
+	if !value.IsNull() {
+		return value.ValueBool()
+	}
+	raw, ok := s.lookup(attribute)
+	if !ok {
+		return fallback
+	}
+	result, err := strconv.ParseBool(raw)
+	if err != nil {
+		s.diags.AddAttributeError(path.Root(attribute), "Invalid Confluence Provider Configuration", fmt.Sprintf("Expected a boolean for %s, got %q", attribute, raw))
+		return fallback
+	}
+	return result
+}
+
+func (s *providerSettings) int(attribute string, value types.Int64, fallback int64) int64 {
+	if s.unknown(attribute, value) {
+		return fallback
+	}
+	if !value.IsNull() {
+		return value.ValueInt64()
+	}
+	raw, ok := s.lookup(attribute)
+	if !ok {
+		return fallback
+	}
+	result, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		s.diags.AddAttributeError(path.Root(attribute), "Invalid Confluence Provider Configuration", fmt.Sprintf("Expected a number for %s, got %q", attribute, raw))
+		return fallback
+	}
+	return result
+}
+
+func (s *providerSettings) required(attribute string, value string, description string) {
+	if value != "" {
+		return
+	}
+	s.diags.AddAttributeError(
+		attributePath(attribute),
+		fmt.Sprintf("Missing Confluence %s", description),
+		fmt.Sprintf("The provider cannot create the Confluence client without the %s. "+
+			"Set %s in the provider configuration, the %s environment variable or the credentials file profile.", strings.ToLower(description), attributePath(attribute), envName(attribute)),
+	)
+}
+
+// loadProfile reads the selected profile of the credentials file. A missing default file or default profile
+// is not an error, the settings may all come from the configuration or the environment.
+func (s *providerSettings) loadProfile() {
+	file := s.string("credentials_file", s.data.CredentialsFile)
+	profile := s.string("profile", s.data.Profile)
+	if s.diags.HasError() {
+		return
+	}
+	explicit := file != "" || profile != ""
+	if file == "" {
+		file = helpers.DefaultCredentialsFile
+	}
+	if profile == "" {
+		profile = helpers.DefaultProfile
+	}
+
+	settings, err := helpers.ReadCredentialsFile(file, profile)
+	if err != nil {
+		if !explicit && (errors.Is(err, fs.ErrNotExist) || errors.Is(err, helpers.ErrProfileNotFound)) {
+			return
 		}
-		publicSite = data.Site.ValueString()
+		s.diags.AddAttributeError(path.Root("credentials_file"), "Invalid Confluence Credentials File", err.Error())
+		return
 	}
+	s.profile = settings
+}
 
-	if !data.SiteTLS.IsNull() {
-		siteTls = data.SiteTLS.ValueBool()
-		publicSiteTls = data.SiteTLS.ValueBool()
+// override returns the value of an auth block attribute when it is set
+func (s *providerSettings) override(value string, attribute string, configured types.String) string {
+	if configured.IsNull() {
+		return value
 	}
+	return s.string(attribute, configured)
+}
 
-	if !data.PublicSite.IsNull() {
-		publicSite = data.PublicSite.ValueString()
+// transport builds the TLS and proxy settings of the client
+func (s *providerSettings) transport(input *helpers.NewClientInput) {
+	transportInput := &helpers.TransportInput{
+		CABundle:           s.string("ca_bundle", s.data.CABundle),
+		ClientCertificate:  s.string("client_certificate", s.data.ClientCertificate),
+		ClientKey:          s.string("client_key", s.data.ClientKey),
+		InsecureSkipVerify: s.bool("insecure_skip_verify", s.data.InsecureSkipVerify, false),
+		ProxyURL:           s.string("proxy_url", s.data.ProxyURL),
 	}
+	if s.diags.HasError() {
+		return
+	}
+	transport, err := helpers.NewTransport(transportInput)
+	if err != nil {
+		s.diags.AddError("Invalid Confluence TLS or Proxy Configuration", err.Error())
+		return
+	}
+	input.Transport = transport
+}
 
-	if !data.PublicSiteTLS.IsNull() {
-		publicSiteTls = data.PublicSiteTLS.ValueBool()
+// attributePath returns the configuration path of a setting, the auth_* settings live in the auth block
+func attributePath(attribute string) path.Path {
+	if strings.HasPrefix(attribute, "auth_") {
+		return path.Root("auth").AtName(strings.TrimPrefix(attribute, "auth_"))
 	}
+	return path.Root(attribute)
+}
+
+func envName(attribute string) string {
+	return "CONFLUENCE_" + strings.ToUpper(attribute)
+}
+
+// clientInputFromProviderModel builds the client configuration and reports missing or invalid settings.
+// The cloud id of OAuth 2.0 clients is looked up with discoverCloudId unless it is configured.
+func clientInputFromProviderModel(ctx context.Context, data *ConfluenceProviderModel, getenv func(string) string, discoverCloudId func(context.Context, string, http.RoundTripper) (string, error)) (*helpers.NewClientInput, diag.Diagnostics) {
+	settings := &providerSettings{data: data, getenv: getenv}
+	settings.loadProfile()
 
-	if !data.Context.IsNull() {
-		context = data.Context.ValueString()
+	input := &helpers.NewClientInput{
+		Site:     settings.string("site", data.Site),
+		Context:  settings.string("context", data.Context),
+		Username: settings.string("user", data.Username),
+		Password: settings.string("token", data.Token),
 	}
+	input.SiteUseTLS = settings.bool("site_tls", data.SiteTLS, true)
+	input.PublicSite = settings.string("public_site", data.PublicSite)
+	input.PublicSiteUseTLS = settings.bool("public_site_tls", data.PublicSiteTLS, input.SiteUseTLS)
+	input.MaxRetries = int(settings.int("max_retries", data.MaxRetries, helpers.DefaultMaxRetries))
+	input.Debug = settings.bool("debug", data.Debug, false)
+	input.MaxRetryWait = time.Duration(settings.int("max_retry_wait", data.MaxRetryWait, int64(helpers.DefaultMaxRetryWait/time.Second))) * time.Second
+	input.Timeout = time.Duration(settings.int("timeout", data.Timeout, int64(helpers.DefaultTimeout/time.Second))) * time.Second
+	settings.transport(input)
 
-	if !data.Username.IsNull() {
-		username = data.Username.ValueString()
+	apiVersion, err := helpers.ParseAPIVersion(settings.string("api_version", data.APIVersion))
+	if err != nil {
+		settings.diags.AddAttributeError(path.Root("api_version"), "Invalid Confluence Provider Configuration", err.Error())
 	}
+	input.APIVersion = apiVersion
 
-	if !data.Token.IsNull() {
-		password = data.Token.ValueString()
+	if input.PublicSite == "" {
+		input.PublicSite = input.Site
+	}
+	input.DeploymentType = settings.string("deployment_type", data.DeploymentType)
+	if input.DeploymentType != "" && !helpers.Contains(helpers.DeploymentTypes, input.DeploymentType) {
+		settings.diags.AddAttributeError(path.Root("deployment_type"), "Invalid Confluence Provider Configuration", fmt.Sprintf("Unsupported deployment type %q, expected one of %v", input.DeploymentType, helpers.DeploymentTypes))
+	}
+	if (strings.HasSuffix(input.Site, ".atlassian.net") || input.DeploymentType == helpers.DeploymentTypeCloud) && input.Context == "" {
+		input.Context = "/wiki"
 	}
 
-	// Example client configuration for data sources and resources
-	client := helpers.NewClient(&helpers.NewClientInput{
-		Site:             site,
-		SiteUseTLS:       siteTls,
-		PublicSite:       publicSite,
-		PublicSiteUseTLS: publicSiteTls,
-		Context:          context,
-		Username:         username,
-		Password:         password,
-	})
+	auth := data.Auth
+	if auth == nil {
+		auth = &ConfluenceProviderAuthModel{
+			Type:         types.StringNull(),
+			User:         types.StringNull(),
+			Token:        types.StringNull(),
+			ClientId:     types.StringNull(),
+			ClientSecret: types.StringNull(),
+			TokenURL:     types.StringNull(),
+			CloudId:      types.StringNull(),
+		}
+	}
+	authType := settings.string("auth_type", auth.Type)
+	input.Username = settings.override(input.Username, "auth_user", auth.User)
+	input.Password = settings.override(input.Password, "auth_token", auth.Token)
+	input.CloudId = settings.string("auth_cloud_id", auth.CloudId)
 
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	settings.required("site", input.Site, "Site")
+	switch authType {
+	case "", helpers.AuthTypeBasic:
+		settings.required("user", input.Username, "User")
+		settings.required("token", input.Password, "Token")
+	case helpers.AuthTypeClientCredentials:
+		clientId := settings.string("auth_client_id", auth.ClientId)
+		clientSecret := settings.string("auth_client_secret", auth.ClientSecret)
+		tokenURL := settings.string("auth_token_url", auth.TokenURL)
+		settings.required("auth_client_id", clientId, "Client Id")
+		settings.required("auth_client_secret", clientSecret, "Client Secret")
+		input.Auth = helpers.BearerTokenSource(helpers.NewClientCredentials(clientId, clientSecret, tokenURL, input.Transport))
+	default:
+		settings.required("token", input.Password, "Token")
+	}
+	if input.Auth == nil {
+		authenticator, err := helpers.NewAuthenticator(authType, input.Username, input.Password)
+		if err != nil {
+			settings.diags.AddAttributeError(attributePath("auth_type"), "Invalid Confluence Provider Configuration", err.Error())
+		}
+		input.Auth = authenticator
+	}
+
+	// OAuth 2.0 tokens are only accepted by the API gateway of Confluence Cloud
+	if input.CloudId == "" && helpers.UsesCloudGateway(authType) && strings.HasSuffix(input.Site, ".atlassian.net") && !settings.diags.HasError() {
+		scheme := "https"
+		if !input.SiteUseTLS {
+			scheme = "http"
+		}
+		cloudId, err := discoverCloudId(ctx, scheme+"://"+input.Site, input.Transport)
+		if err != nil {
+			settings.diags.AddAttributeError(
+				attributePath("auth_cloud_id"),
+				"Unknown Confluence Cloud Id",
+				fmt.Sprintf("The cloud id is needed to route OAuth 2.0 requests through the API gateway, set it in the auth block or with %s: %s", envName("auth_cloud_id"), err),
+			)
+		}
+		input.CloudId = cloudId
+	}
+
+	return input, settings.diags
+}
+
+// requireFeature reports an error when the deployment the client talks to lacks a feature and returns
+// whether the operation can go ahead. Only a warning is reported when the deployment type was guessed
+// from the host name, as the guess may be wrong.
+func requireFeature(client *helpers.Client, feature helpers.Feature, diags *diag.Diagnostics) bool {
+	capabilities := client.Capabilities()
+	if capabilities.Supports(feature) {
+		return true
+	}
+	if capabilities.Guessed() {
+		diags.AddWarning(
+			"Possibly Not Supported on This Deployment",
+			fmt.Sprintf("The site looks like deployment type %q (%s), which does not support %s. Set deployment_type in the provider configuration if this is wrong.", capabilities.DeploymentType, capabilities, feature),
+		)
+		return true
+	}
+	diags.AddError(
+		"Not Supported on This Deployment",
+		fmt.Sprintf("Deployment type %q (%s) does not support %s. Set deployment_type in the provider configuration if the deployment was not detected correctly.", capabilities.DeploymentType, capabilities, feature),
+	)
+	return false
 }
 
 func (p *ConfluenceProvider) Resources(ctx context.Context) []func() resource.Resource {
@@ -150,12 +544,17 @@ func (p *ConfluenceProvider) Resources(ctx context.Context) []func() resource.Re
 		NewSpaceResource,
 		NewSpacePermissionResource,
 		NewGroupMembershipResource,
+		NewGroupMembersResource,
+		NewContentResource,
+		NewAttachmentResource,
 	}
 }
 
 func (p *ConfluenceProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewGroupMembershipDataSource,
+		NewSpaceDataSource,
+		NewSpacesDataSource,
 	}
 }
 
@@ -1,11 +1,23 @@
 package provider
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"terraform-provider-confluence/internal/helpers"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -37,3 +49,318 @@ func testAccPreCheck(t *testing.T) {
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
 }
+
+func noDiscovery(context.Context, string, http.RoundTripper) (string, error) {
+	return "", errors.New("unexpected cloud id lookup")
+}
+
+func TestClientInputFromProviderModel(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	credentials := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(credentials, []byte("[ci]\nsite = example.atlassian.net\nuser = ci@example.com\ntoken = from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	emptyModel := func() *ConfluenceProviderModel {
+		return &ConfluenceProviderModel{
+			Site:            types.StringNull(),
+			SiteTLS:         types.BoolNull(),
+			PublicSite:      types.StringNull(),
+			PublicSiteTLS:   types.BoolNull(),
+			Context:         types.StringNull(),
+			Username:        types.StringNull(),
+			Token:           types.StringNull(),
+			MaxRetries:      types.Int64Null(),
+			MaxRetryWait:    types.Int64Null(),
+			CredentialsFile: types.StringNull(),
+			Profile:         types.StringNull(),
+			Debug:           types.BoolNull(),
+
+			CABundle:           types.StringNull(),
+			ClientCertificate:  types.StringNull(),
+			ClientKey:          types.StringNull(),
+			InsecureSkipVerify: types.BoolNull(),
+			ProxyURL:           types.StringNull(),
+			Timeout:            types.Int64Null(),
+
+			APIVersion:     types.StringNull(),
+			DeploymentType: types.StringNull(),
+		}
+	}
+
+	t.Run("environment", func(t *testing.T) {
+		env := map[string]string{
+			"CONFLUENCE_SITE":        "confluence.example.com",
+			"CONFLUENCE_SITE_TLS":    "false",
+			"CONFLUENCE_USER":        "jdoe",
+			"CONFLUENCE_TOKEN":       "from-env",
+			"CONFLUENCE_MAX_RETRIES": "2",
+		}
+		input, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if input.Site != "confluence.example.com" || input.SiteUseTLS || input.PublicSiteUseTLS || input.Username != "jdoe" || input.Password != "from-env" || input.MaxRetries != 2 {
+			t.Fatalf("unexpected client input: %+v", input)
+		}
+	})
+
+	t.Run("configuration wins", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_SITE": "confluence.example.com", "CONFLUENCE_USER": "jdoe", "CONFLUENCE_TOKEN": "from-env"}
+		data := emptyModel()
+		data.Token = types.StringValue("from-config")
+		input, diags := clientInputFromProviderModel(context.Background(), data, func(key string) string { return env[key] }, noDiscovery)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if input.Password != "from-config" {
+			t.Fatalf("expected the configured token to win, got: %q", input.Password)
+		}
+	})
+
+	t.Run("credentials file", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_CREDENTIALS_FILE": credentials, "CONFLUENCE_PROFILE": "ci"}
+		input, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if input.Site != "example.atlassian.net" || input.Context != "/wiki" || input.Username != "ci@example.com" || input.Password != "from-file" {
+			t.Fatalf("unexpected client input: %+v", input)
+		}
+	})
+
+	t.Run("default file without the default profile", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		if err := os.MkdirAll(filepath.Join(home, ".confluence"), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(home, ".confluence", "credentials"), []byte("[other]\nsite = other.atlassian.net\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		env := map[string]string{"CONFLUENCE_SITE": "confluence.example.com", "CONFLUENCE_USER": "jdoe", "CONFLUENCE_TOKEN": "from-env"}
+		input, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if input.Site != "confluence.example.com" || input.Password != "from-env" {
+			t.Fatalf("unexpected client input: %+v", input)
+		}
+	})
+
+	t.Run("missing profile", func(t *testing.T) {
+		data := emptyModel()
+		data.CredentialsFile = types.StringValue(credentials)
+		data.Profile = types.StringValue("prod")
+		_, diags := clientInputFromProviderModel(context.Background(), data, func(string) string { return "" }, noDiscovery)
+		if !diags.HasError() {
+			t.Fatal("expected an error for a missing profile")
+		}
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		data := emptyModel()
+		data.Site = types.StringValue("example.atlassian.net")
+		_, diags := clientInputFromProviderModel(context.Background(), data, func(string) string { return "" }, noDiscovery)
+		if diags.ErrorsCount() != 2 {
+			t.Fatalf("expected errors for the missing user and token, got: %v", diags)
+		}
+		for _, d := range diags.Errors() {
+			if strings.Contains(d.Detail(), "password") {
+				t.Fatalf("expected no placeholder credentials in: %s", d.Detail())
+			}
+		}
+	})
+
+	t.Run("bearer token", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_SITE": "confluence.example.com", "CONFLUENCE_AUTH_TYPE": "bearer", "CONFLUENCE_TOKEN": "pat"}
+		input, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.HasError() {
+			t.Fatalf("expected no user to be required for bearer tokens, got: %v", diags)
+		}
+		if input.Auth == nil || fmt.Sprint(input.Auth) != "bearer token" {
+			t.Fatalf("expected a bearer token authenticator, got: %v", input.Auth)
+		}
+	})
+
+	t.Run("auth block", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_SITE": "confluence.example.com", "CONFLUENCE_USER": "jdoe", "CONFLUENCE_TOKEN": "api-token"}
+		data := emptyModel()
+		data.Auth = &ConfluenceProviderAuthModel{Type: types.StringValue("oauth"), User: types.StringNull(), Token: types.StringValue("access-token")}
+		input, diags := clientInputFromProviderModel(context.Background(), data, func(key string) string { return env[key] }, noDiscovery)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if input.Password != "access-token" || fmt.Sprint(input.Auth) != "bearer token" {
+			t.Fatalf("expected the auth block token to be used as bearer token, got: %+v", input)
+		}
+	})
+
+	t.Run("client credentials", func(t *testing.T) {
+		env := map[string]string{
+			"CONFLUENCE_SITE":               "example.atlassian.net",
+			"CONFLUENCE_AUTH_TYPE":          "client_credentials",
+			"CONFLUENCE_AUTH_CLIENT_ID":     "client",
+			"CONFLUENCE_AUTH_CLIENT_SECRET": "secret",
+		}
+		var discovered string
+		discover := func(_ context.Context, site string, _ http.RoundTripper) (string, error) {
+			discovered = site
+			return "cloud-123", nil
+		}
+		input, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, discover)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if discovered != "https://example.atlassian.net" || input.CloudId != "cloud-123" {
+			t.Fatalf("expected the cloud id to be looked up, got %q from %q", input.CloudId, discovered)
+		}
+		if fmt.Sprint(input.Auth) != "bearer token source" {
+			t.Fatalf("expected a token source, got: %v", input.Auth)
+		}
+	})
+
+	t.Run("client credentials with cloud id", func(t *testing.T) {
+		data := emptyModel()
+		data.Site = types.StringValue("example.atlassian.net")
+		data.Auth = &ConfluenceProviderAuthModel{
+			Type:         types.StringValue("client_credentials"),
+			ClientId:     types.StringValue("client"),
+			ClientSecret: types.StringValue("secret"),
+			CloudId:      types.StringValue("cloud-456"),
+		}
+		input, diags := clientInputFromProviderModel(context.Background(), data, func(string) string { return "" }, noDiscovery)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if input.CloudId != "cloud-456" {
+			t.Fatalf("expected the configured cloud id, got %q", input.CloudId)
+		}
+	})
+
+	t.Run("client credentials missing secret", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_SITE": "confluence.example.com", "CONFLUENCE_AUTH_TYPE": "client_credentials", "CONFLUENCE_AUTH_CLIENT_ID": "client"}
+		_, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.ErrorsCount() != 1 || !diags.Contains(diag.NewAttributeErrorDiagnostic(path.Root("auth").AtName("client_secret"), diags.Errors()[0].Summary(), diags.Errors()[0].Detail())) {
+			t.Fatalf("expected an error for the missing client secret, got: %v", diags)
+		}
+	})
+
+	t.Run("cloud id lookup fails", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_SITE": "example.atlassian.net", "CONFLUENCE_AUTH_TYPE": "oauth", "CONFLUENCE_TOKEN": "access-token"}
+		_, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.ErrorsCount() != 1 {
+			t.Fatalf("expected an error for the failed cloud id lookup, got: %v", diags)
+		}
+	})
+
+	t.Run("invalid auth type", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_SITE": "confluence.example.com", "CONFLUENCE_AUTH_TYPE": "kerberos", "CONFLUENCE_USER": "jdoe", "CONFLUENCE_TOKEN": "secret"}
+		_, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.ErrorsCount() != 1 {
+			t.Fatalf("expected an error for the unsupported auth type, got: %v", diags)
+		}
+	})
+
+	t.Run("invalid environment", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_SITE": "confluence.example.com", "CONFLUENCE_USER": "jdoe", "CONFLUENCE_TOKEN": "secret", "CONFLUENCE_SITE_TLS": "maybe"}
+		_, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.ErrorsCount() != 1 {
+			t.Fatalf("expected an error for the invalid boolean, got: %v", diags)
+		}
+	})
+
+	t.Run("transport", func(t *testing.T) {
+		env := map[string]string{
+			"CONFLUENCE_SITE":                 "confluence.example.com",
+			"CONFLUENCE_USER":                 "jdoe",
+			"CONFLUENCE_TOKEN":                "secret",
+			"CONFLUENCE_PROXY_URL":            "http://proxy.example.com:3128",
+			"CONFLUENCE_INSECURE_SKIP_VERIFY": "true",
+			"CONFLUENCE_TIMEOUT":              "45",
+		}
+		input, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if input.Timeout != 45*time.Second {
+			t.Fatalf("expected the timeout from the environment, got %s", input.Timeout)
+		}
+		transport, ok := input.Transport.(*http.Transport)
+		if !ok || !transport.TLSClientConfig.InsecureSkipVerify {
+			t.Fatalf("expected a transport that skips the verification, got %#v", input.Transport)
+		}
+		proxy, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "confluence.example.com"}})
+		if err != nil || proxy.String() != "http://proxy.example.com:3128" {
+			t.Fatalf("expected the configured proxy, got %v (%v)", proxy, err)
+		}
+	})
+
+	t.Run("api version", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_SITE": "confluence.example.com", "CONFLUENCE_USER": "jdoe", "CONFLUENCE_TOKEN": "secret", "CONFLUENCE_API_VERSION": "v2"}
+		input, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if input.APIVersion != helpers.APIVersionV2 {
+			t.Fatalf("expected the API version from the environment, got %q", input.APIVersion)
+		}
+
+		env["CONFLUENCE_API_VERSION"] = "v3"
+		_, diags = clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.ErrorsCount() != 1 {
+			t.Fatalf("expected an error for the unsupported API version, got: %v", diags)
+		}
+	})
+
+	t.Run("deployment type", func(t *testing.T) {
+		env := map[string]string{"CONFLUENCE_SITE": "docs.example.com", "CONFLUENCE_USER": "jdoe", "CONFLUENCE_TOKEN": "secret", "CONFLUENCE_DEPLOYMENT_TYPE": "cloud"}
+		input, diags := clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if input.DeploymentType != helpers.DeploymentTypeCloud || input.Context != "/wiki" {
+			t.Fatalf("expected Cloud below /wiki on the custom domain, got %q at %q", input.DeploymentType, input.Context)
+		}
+
+		env["CONFLUENCE_DEPLOYMENT_TYPE"] = "server"
+		_, diags = clientInputFromProviderModel(context.Background(), emptyModel(), func(key string) string { return env[key] }, noDiscovery)
+		if diags.ErrorsCount() != 1 {
+			t.Fatalf("expected an error for the unsupported deployment type, got: %v", diags)
+		}
+	})
+
+	t.Run("client certificate without key", func(t *testing.T) {
+		data := emptyModel()
+		data.ClientCertificate = types.StringValue("-----BEGIN CERTIFICATE-----")
+		env := map[string]string{"CONFLUENCE_SITE": "confluence.example.com", "CONFLUENCE_USER": "jdoe", "CONFLUENCE_TOKEN": "secret"}
+		_, diags := clientInputFromProviderModel(context.Background(), data, func(key string) string { return env[key] }, noDiscovery)
+		if diags.ErrorsCount() != 1 {
+			t.Fatalf("expected an error for the missing client key, got: %v", diags)
+		}
+	})
+}
+
+func TestRequireFeature(t *testing.T) {
+	var diags diag.Diagnostics
+	if !requireFeature(helpers.NewClient(&helpers.NewClientInput{Site: "example.atlassian.net"}), helpers.FeatureGroupIds, &diags) || len(diags) != 0 {
+		t.Fatalf("expected Cloud to support %s, got: %v", helpers.FeatureGroupIds, diags)
+	}
+
+	// A guessed deployment type may be wrong, so it only warns
+	if !requireFeature(helpers.NewClient(&helpers.NewClientInput{Site: "confluence.example.com"}), helpers.FeatureGroupIds, &diags) {
+		t.Fatal("expected a guessed deployment type not to block the operation")
+	}
+	if diags.HasError() || diags.WarningsCount() != 1 || !strings.Contains(diags.Warnings()[0].Detail(), `deployment type "datacenter"`) {
+		t.Fatalf("expected a warning naming the deployment type, got: %v", diags)
+	}
+
+	diags = nil
+	client := helpers.NewClient(&helpers.NewClientInput{Site: "confluence.example.com", DeploymentType: helpers.DeploymentTypeDataCenter})
+	if requireFeature(client, helpers.FeatureGroupIds, &diags) {
+		t.Fatal("expected a configured Data Center deployment to block the operation")
+	}
+	if diags.ErrorsCount() != 1 || !strings.Contains(diags.Errors()[0].Detail(), `Deployment type "datacenter" (Confluence Data Center) does not support groups addressed by id`) {
+		t.Fatalf("expected an error for Data Center, got: %v", diags)
+	}
+}
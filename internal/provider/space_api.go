@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"terraform-provider-confluence/internal/helpers"
+	"terraform-provider-confluence/internal/provider/spacekey"
+	"terraform-provider-confluence/internal/provider/transferobjects"
+)
+
+// spaceAPI reads spaces and their permissions through the REST API version of the client. Both
+// versions return the v1 transfer objects, so the resources do not need to know which one is used.
+// Spaces and permissions are still written through v1, which is available on every deployment.
+type spaceAPI interface {
+	getSpace(ctx context.Context, key string) (*transferobjects.Space, error)
+	getSpacePermissions(ctx context.Context, key string) (*transferobjects.SummarySpacePermissions, error)
+	// listSpaces returns the spaces matching the filter, their descriptions are only available as plain text
+	listSpaces(ctx context.Context, filter spaceFilter) ([]transferobjects.Space, error)
+}
+
+// spaceFilter narrows down the spaces returned by listSpaces, empty fields do not filter
+type spaceFilter struct {
+	Type   string
+	Status string
+	Label  string
+	// Favourite only returns the spaces the current user marked as favourite
+	Favourite bool
+}
+
+// newSpaceAPI returns the adapter for the REST API version of the client
+func newSpaceAPI(client *helpers.Client) spaceAPI {
+	if client.APIVersion() == helpers.APIVersionV2 {
+		return &spaceAPIv2{client: client}
+	}
+	return &spaceAPIv1{client: client}
+}
+
+type spaceAPIv1 struct {
+	client *helpers.Client
+}
+
+func (a *spaceAPIv1) getSpace(ctx context.Context, key string) (*transferobjects.Space, error) {
+	var response transferobjects.Space
+	path := fmt.Sprintf("/rest/api/space/%s?expand=description.plain,description.view,homepage", spacekey.Normalize(key))
+	if err := a.client.Get(ctx, path, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// getSpacePermissions loads all permissions of a space, following the next links of paginated responses
+func (a *spaceAPIv1) getSpacePermissions(ctx context.Context, key string) (*transferobjects.SummarySpacePermissions, error) {
+	var summary *transferobjects.SummarySpacePermissions
+	var visited []string
+	path := fmt.Sprintf("/rest/api/space/%s?expand=permissions", spacekey.Normalize(key))
+	for path != "" && !helpers.Contains(visited, path) {
+		visited = append(visited, path)
+		var response transferobjects.SummarySpacePermissions
+		if err := a.client.Get(ctx, path, &response); err != nil {
+			return nil, err
+		}
+		if summary == nil {
+			summary = &response
+		} else {
+			summary.Permissions = append(summary.Permissions, response.Permissions...)
+		}
+		path = ""
+		if response.Links != nil {
+			path = a.client.RelativePath(response.Links.Next)
+		}
+	}
+	return summary, nil
+}
+
+func (a *spaceAPIv1) listSpaces(ctx context.Context, filter spaceFilter) ([]transferobjects.Space, error) {
+	query := url.Values{}
+	query.Set("expand", "description.plain,homepage")
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.Label != "" {
+		query.Set("label", filter.Label)
+	}
+	if filter.Favourite {
+		query.Set("favourite", "true")
+	}
+
+	var spaces []transferobjects.Space
+	pages := helpers.Paginate[transferobjects.Space](a.client, "/rest/api/space?"+query.Encode())
+	for pages.Next(ctx) {
+		space := pages.Value()
+		// The web UI links of listed spaces are relative to the context of the page
+		if space.Links != nil && space.Links.Context == "" {
+			space.Links.Context = pages.Links().Context
+		}
+		spaces = append(spaces, space)
+	}
+	if err := pages.Err(); err != nil {
+		return nil, err
+	}
+	return spaces, nil
+}
+
+type spaceAPIv2 struct {
+	client *helpers.Client
+}
+
+// findSpace looks up a space by key, v2 addresses spaces by their numeric id only
+func (a *spaceAPIv2) findSpace(ctx context.Context, key string, descriptionFormat string) (*transferobjects.SpaceV2, string, error) {
+	path := fmt.Sprintf("/api/v2/spaces?keys=%s&description-format=%s", url.QueryEscape(spacekey.Normalize(key)), descriptionFormat)
+	var response helpers.Page[transferobjects.SpaceV2]
+	if err := a.client.Get(ctx, path, &response); err != nil {
+		return nil, "", err
+	}
+	for _, space := range response.Results {
+		if spacekey.Equal(space.Key, key) {
+			return &space, response.Links.Base, nil
+		}
+	}
+	return nil, "", &helpers.APIError{
+		Method:     http.MethodGet,
+		Path:       path,
+		StatusCode: http.StatusNotFound,
+		Status:     fmt.Sprintf("%d %s", http.StatusNotFound, http.StatusText(http.StatusNotFound)),
+	}
+}
+
+func (a *spaceAPIv2) getSpace(ctx context.Context, key string) (*transferobjects.Space, error) {
+	space, base, err := a.findSpace(ctx, key, "plain")
+	if err != nil {
+		return nil, err
+	}
+	// Only one description format is returned per request
+	var view transferobjects.SpaceV2
+	path := fmt.Sprintf("/api/v2/spaces/%s?description-format=view", space.Id.String())
+	if err := a.client.Get(ctx, path, &view); err != nil {
+		return nil, err
+	}
+
+	result := spaceFromV2(space, base)
+	if view.Description != nil {
+		result.Description.View = view.Description.View
+	}
+	return result, nil
+}
+
+func (a *spaceAPIv2) listSpaces(ctx context.Context, filter spaceFilter) ([]transferobjects.Space, error) {
+	query := url.Values{}
+	query.Set("description-format", "plain")
+	if filter.Type != "" {
+		query.Set("type", filter.Type)
+	}
+	if filter.Status != "" {
+		query.Set("status", filter.Status)
+	}
+	if filter.Label != "" {
+		query.Set("labels", filter.Label)
+	}
+	if filter.Favourite {
+		// v2 filters favourites by account id only
+		var user transferobjects.Member
+		if err := a.client.Get(ctx, "/rest/api/user/current", &user); err != nil {
+			return nil, err
+		}
+		query.Set("favorited-by", user.AccountID)
+	}
+
+	var spaces []transferobjects.Space
+	pages := helpers.Paginate[transferobjects.SpaceV2](a.client, "/api/v2/spaces?"+query.Encode())
+	for pages.Next(ctx) {
+		space := pages.Value()
+		spaces = append(spaces, *spaceFromV2(&space, pages.Links().Base))
+	}
+	if err := pages.Err(); err != nil {
+		return nil, err
+	}
+	return spaces, nil
+}
+
+// spaceFromV2 converts a v2 space into the v1 transfer object. The base URL is returned next to the
+// results and includes the context the web UI link is relative to.
+func spaceFromV2(space *transferobjects.SpaceV2, base string) *transferobjects.Space {
+	result := &transferobjects.Space{
+		Id:          space.Id,
+		Key:         space.Key,
+		Name:        space.Name,
+		Type:        space.Type,
+		Status:      space.Status,
+		Description: &transferobjects.SpaceDescription{},
+	}
+	if space.Description != nil {
+		result.Description.Plain = space.Description.Plain
+	}
+	if space.HomepageId != "" {
+		result.Homepage = &transferobjects.SpaceHomepage{Id: space.HomepageId}
+	}
+	if space.Links != nil && space.Links.WebUI != "" {
+		result.Links = &transferobjects.SpaceLinks{WebUI: space.Links.WebUI}
+		if baseURL, err := url.Parse(base); err == nil {
+			result.Links.Context = baseURL.Path
+		}
+	}
+	return result
+}
+
+// getSpacePermissions loads all permissions of a space with cursor pagination. Groups are only identified
+// by their id in v2, their names are looked up through v1 so that they match the configuration.
+func (a *spaceAPIv2) getSpacePermissions(ctx context.Context, key string) (*transferobjects.SummarySpacePermissions, error) {
+	space, _, err := a.findSpace(ctx, key, "plain")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &transferobjects.SummarySpacePermissions{
+		ID:     space.Id,
+		Key:    space.Key,
+		Name:   space.Name,
+		Type:   space.Type,
+		Status: space.Status,
+	}
+	groupNames := make(map[string]string)
+	permissions := helpers.Paginate[transferobjects.SpacePermissionV2](a.client, fmt.Sprintf("/api/v2/spaces/%s/permissions", space.Id.String()))
+	for permissions.Next(ctx) {
+		permission := permissions.Value()
+		saved := transferobjects.SavedPermission{
+			ID: permission.Id.Int(),
+			Operation: transferobjects.SavedPermissionOperation{
+				Operation:  permission.Operation.Key,
+				TargetType: permission.Operation.TargetType,
+			},
+		}
+		switch permission.Principal.Type {
+		case "user":
+			saved.Subjects.User = &transferobjects.SavedPermissionUser{
+				Results: []transferobjects.SavedPermissionUserResult{{Type: "known", AccountID: permission.Principal.Id}},
+				Size:    1,
+			}
+		case "group":
+			name, ok := groupNames[permission.Principal.Id]
+			if !ok {
+				var group transferobjects.Group
+				if err := a.client.Get(ctx, fmt.Sprintf("/rest/api/group/by-id?id=%s", url.QueryEscape(permission.Principal.Id)), &group); err != nil {
+					return nil, err
+				}
+				name = group.Name
+				groupNames[permission.Principal.Id] = name
+			}
+			saved.Subjects.Group = &transferobjects.SavedPermissionGroup{
+				Results: []transferobjects.SavedPermissionGroupResult{{Type: "group", ID: permission.Principal.Id, Name: name}},
+				Size:    1,
+			}
+		default:
+			// Roles have no counterpart in the resources
+			continue
+		}
+		summary.Permissions = append(summary.Permissions, saved)
+	}
+	if err := permissions.Err(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"terraform-provider-confluence/internal/helpers"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func newSpaceAPIv2TestClient(t *testing.T) (*helpers.Client, *[]string) {
+	var requests []string
+	responses := map[string]string{
+		"/wiki/api/v2/spaces?keys=DOCS&description-format=plain": `{
+			"results": [{"id": "98304", "key": "DOCS", "name": "Docs", "type": "global", "status": "current", "homepageId": "131073",
+				"description": {"plain": {"value": "Team docs", "representation": "plain"}}, "_links": {"webui": "/spaces/DOCS"}}],
+			"_links": {"base": "https://example.atlassian.net/wiki"}}`,
+		"/wiki/api/v2/spaces?keys=MISSING&description-format=plain": `{"results": [], "_links": {"base": "https://example.atlassian.net/wiki"}}`,
+		"/wiki/api/v2/spaces/98304?description-format=view":         `{"id": "98304", "key": "DOCS", "description": {"view": {"value": "<p>Team docs</p>", "representation": "view"}}}`,
+		"/wiki/api/v2/spaces/98304/permissions?limit=200": `{
+			"results": [
+				{"id": "1", "principal": {"type": "user", "id": "account-1"}, "operation": {"key": "read", "targetType": "space"}},
+				{"id": "2", "principal": {"type": "group", "id": "group-1"}, "operation": {"key": "read", "targetType": "space"}}],
+			"_links": {"next": "/wiki/api/v2/spaces/98304/permissions?limit=200&cursor=next-page"}}`,
+		"/wiki/api/v2/spaces/98304/permissions?limit=200&cursor=next-page": `{
+			"results": [
+				{"id": "3", "principal": {"type": "group", "id": "group-1"}, "operation": {"key": "create", "targetType": "page"}},
+				{"id": "4", "principal": {"type": "role", "id": "role-1"}, "operation": {"key": "read", "targetType": "space"}}]}`,
+		"/wiki/rest/api/group/by-id?id=group-1": `{"id": "group-1", "name": "confluence-users"}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		response, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, _ := url.Parse(server.URL)
+	client := helpers.NewClient(&helpers.NewClientInput{Site: serverURL.Host, PublicSite: serverURL.Host, Context: "/wiki", APIVersion: helpers.APIVersionV2})
+	return client, &requests
+}
+
+func TestSpaceAPIv2GetSpace(t *testing.T) {
+	client, _ := newSpaceAPIv2TestClient(t)
+	api := newSpaceAPI(client)
+
+	space, err := api.getSpace(context.Background(), "docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if space.Id.Int() != 98304 || space.Key != "DOCS" || space.Name != "Docs" || space.Type != "global" || space.Status != "current" {
+		t.Fatalf("unexpected space: %+v", space)
+	}
+	if space.Description.Plain.Value != "Team docs" || space.Description.View.Value != "<p>Team docs</p>" {
+		t.Fatalf("expected both description formats, got %+v %+v", space.Description.Plain, space.Description.View)
+	}
+	if space.Homepage == nil || space.Homepage.Id != "131073" {
+		t.Fatalf("unexpected homepage: %+v", space.Homepage)
+	}
+	if space.Links.Context+space.Links.WebUI != "/wiki/spaces/DOCS" {
+		t.Fatalf("unexpected links: %+v", space.Links)
+	}
+
+	if _, err := api.getSpace(context.Background(), "MISSING"); !helpers.IsNotFound(err) {
+		t.Fatalf("expected a not found error, got: %v", err)
+	}
+}
+
+func TestSpaceAPIv2GetSpacePermissions(t *testing.T) {
+	client, requests := newSpaceAPIv2TestClient(t)
+
+	summary, err := newSpaceAPI(client).getSpacePermissions(context.Background(), "DOCS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Key != "DOCS" || len(summary.Permissions) != 3 {
+		t.Fatalf("expected the user and group permissions of both pages, got %+v", summary)
+	}
+
+	group := &SpacePermissionResourceModel{Group: types.StringValue("confluence-users"), User: types.StringNull(), Anonymous: types.BoolNull()}
+	operationIds, _ := operationIdsFromSummaryResponse(context.Background(), group, summary)
+	if !reflect.DeepEqual(operationIds, map[string]string{"read:space": "2", "create:page": "3"}) {
+		t.Fatalf("expected the permissions of the group, got %v", operationIds)
+	}
+	user := &SpacePermissionResourceModel{Group: types.StringNull(), User: types.StringValue("account-1"), Anonymous: types.BoolNull()}
+	operationIds, _ = operationIdsFromSummaryResponse(context.Background(), user, summary)
+	if !reflect.DeepEqual(operationIds, map[string]string{"read:space": "1"}) {
+		t.Fatalf("expected the permissions of the user, got %v", operationIds)
+	}
+
+	groupLookups := 0
+	for _, request := range *requests {
+		if strings.HasPrefix(request, "/wiki/rest/api/group/by-id") {
+			groupLookups++
+		}
+	}
+	if groupLookups != 1 {
+		t.Fatalf("expected the group name to be looked up once, got %d lookups", groupLookups)
+	}
+}
+
+func newSpaceListTestClient(t *testing.T, apiVersion helpers.APIVersion, responses map[string]string) *helpers.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			t.Errorf("unexpected request %s", r.URL.RequestURI())
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, _ := url.Parse(server.URL)
+	return helpers.NewClient(&helpers.NewClientInput{Site: serverURL.Host, PublicSite: serverURL.Host, Context: "/wiki", APIVersion: apiVersion})
+}
+
+func TestSpaceAPIv1ListSpaces(t *testing.T) {
+	client := newSpaceListTestClient(t, helpers.APIVersionV1, map[string]string{
+		"/wiki/rest/api/space?expand=description.plain%2Chomepage&label=team&limit=200&status=current&type=global": `{
+			"results": [
+				{"id": 2, "key": "OPS", "name": "Operations", "type": "global", "status": "current", "homepage": {"id": "20"}, "_links": {"webui": "/spaces/OPS"}},
+				{"id": 1, "key": "DOCS", "name": "Docs", "type": "global", "status": "current", "description": {"plain": {"value": "Team docs"}}, "_links": {"webui": "/spaces/DOCS"}}],
+			"start": 0, "limit": 2, "size": 2, "_links": {"context": "/wiki", "next": "/rest/api/space?expand=description.plain%2Chomepage&label=team&limit=2&start=2&status=current&type=global"}}`,
+		"/wiki/rest/api/space?expand=description.plain%2Chomepage&label=team&limit=2&start=2&status=current&type=global": `{
+			"results": [{"id": 3, "key": "ARCH", "name": "Architecture", "type": "global", "status": "current", "_links": {"webui": "/spaces/ARCH"}}],
+			"start": 2, "limit": 2, "size": 1, "_links": {"context": "/wiki"}}`,
+	})
+
+	spaces, err := newSpaceAPI(client).listSpaces(context.Background(), spaceFilter{Type: "global", Status: "current", Label: "team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := spacesDataSourceSpaces(client, spaces)
+	if len(items) != 3 || items[0].Key.ValueString() != "ARCH" || items[1].Key.ValueString() != "DOCS" || items[2].Key.ValueString() != "OPS" {
+		t.Fatalf("expected the spaces of both pages ordered by key, got %+v", items)
+	}
+	if items[1].Description.ValueString() != "Team docs" || !items[1].Homepage.IsNull() || items[2].Homepage.ValueString() != "20" {
+		t.Fatalf("unexpected description or homepage: %+v", items)
+	}
+	if !strings.HasSuffix(items[1].Url.ValueString(), "/wiki/spaces/DOCS") {
+		t.Fatalf("expected the web UI link below the context, got %s", items[1].Url.ValueString())
+	}
+}
+
+func TestSpaceAPIv2ListSpaces(t *testing.T) {
+	client := newSpaceListTestClient(t, helpers.APIVersionV2, map[string]string{
+		"/wiki/rest/api/user/current": `{"accountId": "account-1"}`,
+		"/wiki/api/v2/spaces?description-format=plain&favorited-by=account-1&limit=200&type=personal": `{
+			"results": [{"id": "98304", "key": "~account-1", "name": "Me", "type": "personal", "status": "current", "homepageId": "131073", "_links": {"webui": "/spaces/~account-1"}}],
+			"_links": {"base": "https://example.atlassian.net/wiki", "next": "/wiki/api/v2/spaces?description-format=plain&favorited-by=account-1&limit=200&type=personal&cursor=next-page"}}`,
+		"/wiki/api/v2/spaces?description-format=plain&favorited-by=account-1&limit=200&type=personal&cursor=next-page": `{
+			"results": [{"id": "98305", "key": "~account-2", "name": "Colleague", "type": "personal", "status": "current"}],
+			"_links": {"base": "https://example.atlassian.net/wiki"}}`,
+	})
+
+	spaces, err := newSpaceAPI(client).listSpaces(context.Background(), spaceFilter{Type: "personal", Favourite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spaces) != 2 || spaces[0].Id.Int() != 98304 || spaces[1].Key != "~account-2" {
+		t.Fatalf("expected the spaces of both pages, got %+v", spaces)
+	}
+	if spaces[0].Homepage == nil || spaces[0].Homepage.Id != "131073" || spaces[0].Links.Context+spaces[0].Links.WebUI != "/wiki/spaces/~account-1" {
+		t.Fatalf("unexpected homepage or links: %+v %+v", spaces[0].Homepage, spaces[0].Links)
+	}
+	if spaces[1].Links != nil {
+		t.Fatalf("expected no links for a space without a web UI link, got %+v", spaces[1].Links)
+	}
+}
+
+func TestSpaceAPIv1GetSpacePermissions(t *testing.T) {
+	client := newSpaceListTestClient(t, helpers.APIVersionV1, map[string]string{
+		"/wiki/rest/api/space/DOCS?expand=permissions": `{
+			"id": 98304, "key": "DOCS",
+			"permissions": [{"id": 1, "subjects": {"user": {"results": [{"type": "known", "accountId": "account-1"}], "size": 1}}, "operation": {"operation": "read", "targetType": "space"}}],
+			"_links": {"next": "https://example.atlassian.net/wiki/rest/api/space/DOCS?expand=permissions&start=1"}}`,
+		"/wiki/rest/api/space/DOCS?expand=permissions&start=1": `{
+			"id": 98304, "key": "DOCS",
+			"permissions": [{"id": 2, "subjects": {"user": {"results": [{"type": "known", "accountId": "account-1"}], "size": 1}}, "operation": {"operation": "create", "targetType": "page"}}]}`,
+	})
+
+	summary, err := newSpaceAPI(client).getSpacePermissions(context.Background(), "docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &SpacePermissionResourceModel{Group: types.StringNull(), User: types.StringValue("account-1"), Anonymous: types.BoolNull()}
+	operationIds, _ := operationIdsFromSummaryResponse(context.Background(), user, summary)
+	if !reflect.DeepEqual(operationIds, map[string]string{"read:space": "1", "create:page": "2"}) {
+		t.Fatalf("expected the permissions of both pages, got %v", operationIds)
+	}
+}
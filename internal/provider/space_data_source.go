@@ -0,0 +1,127 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-confluence/internal/helpers"
+	"terraform-provider-confluence/internal/provider/spacekey"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &SpaceDataSource{}
+
+func NewSpaceDataSource() datasource.DataSource {
+	return &SpaceDataSource{}
+}
+
+// SpaceDataSource defines the data source implementation. It shares the model of SpaceResource.
+type SpaceDataSource struct {
+	client *helpers.Client
+}
+
+func (d *SpaceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_space"
+}
+
+func (d *SpaceDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Space data source, looks up an existing space by its key",
+
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The space key of the confluence space",
+				Required:            true,
+				Validators: []validator.String{
+					spacekey.Validator(),
+				},
+			},
+			"name": schema.StringAttribute{
+				MarkdownDescription: "The name of the space",
+				Computed:            true,
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "The plain text description of the space",
+				Computed:            true,
+			},
+			"description_view": schema.StringAttribute{
+				MarkdownDescription: "The description of the space as rendered by Confluence",
+				Computed:            true,
+			},
+			"homepage": schema.StringAttribute{
+				MarkdownDescription: "The id of the homepage of the space",
+				Computed:            true,
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the space, either `global` or `personal`",
+				Computed:            true,
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The status of the space, either `current` or `archived`",
+				Computed:            true,
+			},
+			"url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the space",
+				Computed:            true,
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "The id of the space",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *SpaceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*helpers.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *SpaceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SpaceResourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Get the space through the API
+	response, err := newSpaceAPI(d.client).getSpace(ctx, data.Key.ValueString())
+	if err != nil {
+		if helpers.IsNotFound(err) {
+			resp.Diagnostics.AddError("Space Not Found", fmt.Sprintf("No space with the key [%s] exists or it is not visible to the configured user", data.Key.ValueString()))
+			return
+		}
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+	setSpaceAttributes(d.client, &data, response)
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, "read a data source")
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
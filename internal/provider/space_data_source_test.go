@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"terraform-provider-confluence/internal/fakeserver"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSpaceDataSource(t *testing.T) {
+	debug := true
+	apiServerObjects := make(map[string]map[string]interface{})
+	testSpaceObject := generateTestSpaceObject()
+
+	svr := fakeserver.NewFakeServer(testPost, apiServerObjects, false, debug, "")
+	defer svr.Shutdown()
+
+	svr.SetSplice("/rest/api/space/"+testSpaceObject.Key, func(a string, b []byte) (string, map[string]interface{}) {
+		jsonStr, _ := json.Marshal(testSpaceObject)
+		var obj map[string]interface{}
+		_ = json.Unmarshal(jsonStr, &obj)
+		return testSpaceObject.Id.String(), obj
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			svr.StartInBackground()
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccSpaceDataSourceConfig("test", testSpaceObject.Key),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.confluence_space.test", "key", testSpaceObject.Key),
+					resource.TestCheckResourceAttr("data.confluence_space.test", "name", testSpaceObject.Name),
+					resource.TestCheckResourceAttr("data.confluence_space.test", "id", testSpaceObject.Id.String()),
+				),
+			},
+			// Not found testing
+			{
+				PreConfig: func() {
+					svr.InjectResponses(1, http.StatusNotFound, nil)
+				},
+				Config:      testAccSpaceDataSourceConfig("test", "MISSING"),
+				ExpectError: regexp.MustCompile("Space Not Found"),
+			},
+		},
+	})
+}
+
+func testAccSpaceDataSourceConfig(name string, key string) string {
+	return fmt.Sprintf(`%s
+data "confluence_space" "%s" {
+	key = "%s"
+}
+`, providerConfig, name, key)
+}
@@ -3,25 +3,32 @@ package provider
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
-	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"sort"
 	"strconv"
 	"strings"
 	"terraform-provider-confluence/internal/helpers"
+	"terraform-provider-confluence/internal/provider/spacekey"
 	"terraform-provider-confluence/internal/provider/transferobjects"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ resource.Resource = &SpacePermissionResource{}
 var _ resource.ResourceWithImportState = &SpacePermissionResource{}
+var _ resource.ResourceWithConfigValidators = &SpacePermissionResource{}
+var _ resource.ResourceWithValidateConfig = &SpacePermissionResource{}
+var _ resource.ResourceWithUpgradeState = &SpacePermissionResource{}
 var validPermissions = []string{
 	"create:page", "create:blogpost", "create:comment", "create:attachment",
 	"read:space",
@@ -44,12 +51,21 @@ type SpacePermissionResource struct {
 // SpacePermissionResourceModel describes the resource data model.
 type SpacePermissionResourceModel struct {
 	Key          types.String `tfsdk:"key"`
-	Operations   types.List   `tfsdk:"operations"`
+	Operations   types.Set    `tfsdk:"operations"`
 	OperationIds types.Map    `tfsdk:"operation_ids"`
 	Group        types.String `tfsdk:"group"`
+	User         types.String `tfsdk:"user"`
+	Anonymous    types.Bool   `tfsdk:"anonymous"`
 	Id           types.String `tfsdk:"id"`
 }
 
+// permissionSubject identifies who a space permission is granted to
+type permissionSubject struct {
+	// Type is one of group, user or anonymous
+	Type       string
+	Identifier string
+}
+
 func (r *SpacePermissionResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_space_permission"
 }
@@ -58,39 +74,59 @@ func (r *SpacePermissionResource) Schema(ctx context.Context, req resource.Schem
 	resp.Schema = schema.Schema{
 		// This description is used by the documentation generator and the language server.
 		MarkdownDescription: "Space permission resource",
+		// Version 1 identifies the resource by its space and subject instead of by its permission ids,
+		// version 2 stores the operations as a set
+		Version: 2,
 
 		Attributes: map[string]schema.Attribute{
 			"key": schema.StringAttribute{
 				MarkdownDescription: "The space key of the confluence space (all caps)",
 				Required:            true,
+				Validators: []validator.String{
+					spacekey.Validator(),
+				},
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					spacekey.RequiresReplace(),
 				},
 			},
-			"operations": schema.ListAttribute{
-				MarkdownDescription: "The operations allowed for the group",
+			"operations": schema.SetAttribute{
+				MarkdownDescription: "The operations allowed for the subject",
 				ElementType:         types.StringType,
 				Required:            true,
-				PlanModifiers: []planmodifier.List{
-					listplanmodifier.RequiresReplace(),
+				Validators: []validator.Set{
+					spacePermissionOperations(),
 				},
 			},
 			"operation_ids": schema.MapAttribute{
-				MarkdownDescription: "The operation's ids for the group",
+				MarkdownDescription: "The operation's ids for the subject",
 				ElementType:         types.StringType,
 				Optional:            true,
 				Computed:            true,
 			},
 			"group": schema.StringAttribute{
-				MarkdownDescription: "The group that is allowed",
-				Required:            true,
+				MarkdownDescription: "The group that is allowed, conflicts with `user` and `anonymous`",
+				Optional:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"user": schema.StringAttribute{
+				MarkdownDescription: "The user that is allowed, identified by account id (Cloud) or user key (Server), conflicts with `group` and `anonymous`",
+				Optional:            true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"anonymous": schema.BoolAttribute{
+				MarkdownDescription: "Set to `true` to grant the operations to anonymous users, conflicts with `group` and `user`",
+				Optional:            true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
 			"id": schema.StringAttribute{
 				Computed:            true,
-				MarkdownDescription: "Resource identifier",
+				MarkdownDescription: "Resource identifier in the format `SPACEKEY/group/GROUPNAME`, `SPACEKEY/user/ACCOUNTID` or `SPACEKEY/anonymous`",
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
 				},
@@ -99,6 +135,30 @@ func (r *SpacePermissionResource) Schema(ctx context.Context, req resource.Schem
 	}
 }
 
+func (r *SpacePermissionResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.ExactlyOneOf(
+			path.MatchRoot("group"),
+			path.MatchRoot("user"),
+			path.MatchRoot("anonymous"),
+		),
+	}
+}
+
+func (r *SpacePermissionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var anonymous types.Bool
+
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("anonymous"), &anonymous)...)
+
+	if !anonymous.IsNull() && !anonymous.IsUnknown() && !anonymous.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("anonymous"),
+			"Invalid Attribute Value",
+			"anonymous can only be set to true, remove it to grant the operations to a group or user instead",
+		)
+	}
+}
+
 func (r *SpacePermissionResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
@@ -132,16 +192,14 @@ func (r *SpacePermissionResource) Create(ctx context.Context, req resource.Creat
 	permissionRequests := spacePermissionMappingFromResourceModel(ctx, data)
 
 	// Create the rule through API
-	var createdIds []string
 	var elements = make(map[string]attr.Value)
 	for _, body := range permissionRequests {
 		var response transferobjects.SpacePermission
-		path := fmt.Sprintf("/rest/api/space/%s/permission", data.Key.ValueString())
-		if err := r.client.Post(path, body, &response, []string{}); err != nil {
+		path := fmt.Sprintf("/rest/api/space/%s/permission", spacekey.Normalize(data.Key.ValueString()))
+		if err := r.client.Post(ctx, path, body, &response, []string{}); err != nil {
 			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
 			return
 		}
-		createdIds = append(createdIds, response.Id.String())
 		key := fmt.Sprintf("%s:%s", body.Operation.Key, body.Operation.Target)
 		elements[key] = types.StringValue(response.Id.String())
 	}
@@ -149,8 +207,7 @@ func (r *SpacePermissionResource) Create(ctx context.Context, req resource.Creat
 	data.OperationIds, _ = types.MapValue(types.StringType, elements)
 
 	// Save id into the Terraform state.
-	sort.Strings(createdIds)
-	data.Id = types.StringValue(strings.Join(createdIds[:], ":"))
+	data.Id = types.StringValue(spacePermissionId(data))
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -166,17 +223,54 @@ func (r *SpacePermissionResource) Read(ctx context.Context, req resource.ReadReq
 		return
 	}
 
+	// The v2 API does not report anonymous access, so it is always read through v1
+	api := newSpaceAPI(r.client)
+	if subjectFromResourceModel(data).Type == "anonymous" {
+		api = &spaceAPIv1{client: r.client}
+	}
+
 	// Get the rule through the API
-	var response transferobjects.SummarySpacePermissions
-	path := fmt.Sprintf("/rest/api/space/%s?expand=permissions", data.Key.ValueString())
-	if err := r.client.Get(path, &response); err != nil {
+	response, err := api.getSpacePermissions(ctx, data.Key.ValueString())
+	if err != nil {
+		if helpers.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Space [%s] no longer exists, removing permissions from state", data.Key.ValueString()))
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
 		return
 	}
 
-	resourceId, operationIds := generateIdFromSummaryResponse(ctx, data, &response)
-	data.OperationIds, _ = types.MapValue(types.StringType, operationIds)
-	data.Id = types.StringValue(resourceId)
+	operationIds, truncated := operationIdsFromSummaryResponse(ctx, data, response)
+
+	// The subject lists of some operations were cut short, look the subject up in the individual permissions
+	if len(truncated) > 0 {
+		subject := subjectFromResourceModel(data)
+		subjectIds, err := subjectOperationIds(ctx, r.client, data.Key.ValueString(), subject, truncated)
+		if err == nil {
+			for operation, permissionId := range subjectIds {
+				operationIds[operation] = permissionId
+			}
+		} else {
+			// Operations that may still be granted are kept as they are
+			var knownIds map[string]string
+			data.OperationIds.ElementsAs(ctx, &knownIds, false)
+			for operation, permissionId := range knownIds {
+				if _, ok := operationIds[operation]; !ok && helpers.Contains(truncated, operation) {
+					tflog.Warn(ctx, fmt.Sprintf("Subjects of permission [%s] in space [%s] are truncated and could not be read, assuming it is still granted: %s", operation, data.Key.ValueString(), err))
+					operationIds[operation] = permissionId
+				}
+			}
+		}
+	}
+
+	if len(operationIds) == 0 {
+		subject := subjectFromResourceModel(data)
+		tflog.Warn(ctx, fmt.Sprintf("%s [%s] has no permissions left in space [%s], removing it from state", subject.Type, subject.Identifier, data.Key.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	setGrantedOperations(ctx, data, operationIds)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -184,17 +278,106 @@ func (r *SpacePermissionResource) Read(ctx context.Context, req resource.ReadReq
 
 func (r *SpacePermissionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data *SpacePermissionResourceModel
+	var state *SpacePermissionResourceModel
+	var plannedOperations []string
+	var currentIds map[string]string
 
-	// Read Terraform plan data into the model
+	// Read Terraform plan and prior state data into the models
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(data.Operations.ElementsAs(ctx, &plannedOperations, false)...)
+	resp.Diagnostics.Append(state.OperationIds.ElementsAs(ctx, &currentIds, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if currentIds == nil {
+		currentIds = make(map[string]string)
+	}
+
+	// Diff the granted operations against the planned ones
+	var toAdd, toRemove []string
+	for _, operation := range plannedOperations {
+		if _, ok := currentIds[operation]; !ok && !helpers.Contains(toAdd, operation) {
+			toAdd = append(toAdd, operation)
+		}
+	}
+	for operation := range currentIds {
+		if !helpers.Contains(plannedOperations, operation) {
+			toRemove = append(toRemove, operation)
+		}
+	}
+	toRemove = revocationOrder(toRemove)
+
+	// Grant the new operations through the API
+	for _, body := range spacePermissionMappingFromOperations(subjectFromResourceModel(data), toAdd) {
+		var response transferobjects.SpacePermission
+		path := fmt.Sprintf("/rest/api/space/%s/permission", spacekey.Normalize(data.Key.ValueString()))
+		if err := r.client.Post(ctx, path, body, &response, []string{}); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
+			r.saveGrantedOperations(ctx, resp, data, currentIds)
+			return
+		}
+		key := fmt.Sprintf("%s:%s", body.Operation.Key, body.Operation.Target)
+		currentIds[key] = response.Id.String()
+	}
+
+	// Revoke the removed operations through the API
+	revokeIds := currentIds
+	if len(toRemove) > 0 {
+		var err error
+		if revokeIds, err = r.v1PermissionIds(ctx, data, currentIds); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+			r.saveGrantedOperations(ctx, resp, data, currentIds)
+			return
+		}
+	}
+	for _, operation := range toRemove {
+		permissionId, ok := revokeIds[operation]
+		if !ok {
+			// The operation is no longer granted
+			delete(currentIds, operation)
+			continue
+		}
+		path := fmt.Sprintf("/rest/api/space/%s/permission/%s", spacekey.Normalize(data.Key.ValueString()), permissionId)
+		if err := r.client.Delete(ctx, path); err != nil && !helpers.IsNotFound(err) {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error while revoking permission [%s][%s], got error: %s", operation, permissionId, err))
+			r.saveGrantedOperations(ctx, resp, data, currentIds)
+			return
+		}
+		delete(currentIds, operation)
+	}
+
+	r.saveGrantedOperations(ctx, resp, data, currentIds)
+}
 
-	// Update not supported
-	resp.Diagnostics.AddError("Schema Error", fmt.Sprintf("UPDATE operation not supported"))
+// saveGrantedOperations stores the operations that are actually granted, also after a partial update
+func (r *SpacePermissionResource) saveGrantedOperations(ctx context.Context, resp *resource.UpdateResponse, data *SpacePermissionResourceModel, operationIds map[string]string) {
+	setGrantedOperations(ctx, data, operationIds)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// setGrantedOperations writes the granted operations into the model
+func setGrantedOperations(ctx context.Context, data *SpacePermissionResourceModel, operationIds map[string]string) {
+	var operations = make([]attr.Value, 0, len(operationIds))
+	var elements = make(map[string]attr.Value)
+	for operation, permissionId := range operationIds {
+		operations = append(operations, types.StringValue(operation))
+		elements[operation] = types.StringValue(permissionId)
+	}
+
+	data.Operations, _ = types.SetValue(types.StringType, operations)
+	data.OperationIds, _ = types.MapValue(types.StringType, elements)
+	data.Id = types.StringValue(spacePermissionId(data))
+}
+
 func (r *SpacePermissionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data *SpacePermissionResourceModel
 	var permissions map[string]string
@@ -208,10 +391,24 @@ func (r *SpacePermissionResource) Delete(ctx context.Context, req resource.Delet
 
 	data.OperationIds.ElementsAs(ctx, &permissions, false)
 
+	permissions, err := r.v1PermissionIds(ctx, data, permissions)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
 	// Get the rule through the API
-	for permission, permissionId := range permissions {
-		path := fmt.Sprintf("/rest/api/space/%s/permission/%s", data.Key.ValueString(), permissionId)
-		if err := r.client.Delete(path); err != nil {
+	operations := make([]string, 0, len(permissions))
+	for operation := range permissions {
+		operations = append(operations, operation)
+	}
+	for _, permission := range revocationOrder(operations) {
+		permissionId := permissions[permission]
+		path := fmt.Sprintf("/rest/api/space/%s/permission/%s", spacekey.Normalize(data.Key.ValueString()), permissionId)
+		if err := r.client.Delete(ctx, path); err != nil {
+			if helpers.IsNotFound(err) {
+				continue
+			}
 			errorMsg := fmt.Sprintf("Error while deleting permission [%s][%s]: %s", permission, permissionId, err.Error())
 			tflog.Warn(ctx, errorMsg)
 			resp.Diagnostics.AddWarning("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
@@ -220,52 +417,316 @@ func (r *SpacePermissionResource) Delete(ctx context.Context, req resource.Delet
 
 }
 
+// v1PermissionIds returns the ids of the granted operations that the v1 API revokes. The v2 API reads
+// permissions with ids of its own, so on v2 the ids are looked up again through v1.
+func (r *SpacePermissionResource) v1PermissionIds(ctx context.Context, data *SpacePermissionResourceModel, operationIds map[string]string) (map[string]string, error) {
+	if r.client.APIVersion() != helpers.APIVersionV2 {
+		return operationIds, nil
+	}
+	response, err := (&spaceAPIv1{client: r.client}).getSpacePermissions(ctx, data.Key.ValueString())
+	if err != nil {
+		if helpers.IsNotFound(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	v1Ids, _ := operationIdsFromSummaryResponse(ctx, data, response)
+	return v1Ids, nil
+}
+
 func (r *SpacePermissionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	key, subject, ok := parseSpacePermissionId(req.ID)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier in the format SPACEKEY/group/GROUPNAME, SPACEKEY/user/ACCOUNTID or SPACEKEY/anonymous, got: %q", req.ID),
+		)
+		return
+	}
+
+	// The operations are read from the API afterwards
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), key)...)
+	switch subject.Type {
+	case "anonymous":
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("anonymous"), true)...)
+	default:
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(subject.Type), subject.Identifier)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+}
+
+func (r *SpacePermissionResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 only granted operations to groups and joined the sorted permission ids with colons
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"key":           schema.StringAttribute{Required: true},
+					"operations":    schema.ListAttribute{ElementType: types.StringType, Required: true},
+					"operation_ids": schema.MapAttribute{ElementType: types.StringType, Optional: true, Computed: true},
+					"group":         schema.StringAttribute{Required: true},
+					"id":            schema.StringAttribute{Computed: true},
+				},
+			},
+			StateUpgrader: upgradeSpacePermissionStateV0,
+		},
+		// Version 1 stored the operations as a list
+		1: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"key":           schema.StringAttribute{Required: true},
+					"operations":    schema.ListAttribute{ElementType: types.StringType, Required: true},
+					"operation_ids": schema.MapAttribute{ElementType: types.StringType, Optional: true, Computed: true},
+					"group":         schema.StringAttribute{Optional: true},
+					"user":          schema.StringAttribute{Optional: true},
+					"anonymous":     schema.BoolAttribute{Optional: true},
+					"id":            schema.StringAttribute{Computed: true},
+				},
+			},
+			StateUpgrader: upgradeSpacePermissionStateV1,
+		},
+	}
+}
+
+// spacePermissionResourceModelV0 describes the data model of schema version 0
+type spacePermissionResourceModelV0 struct {
+	Key          types.String `tfsdk:"key"`
+	Operations   types.List   `tfsdk:"operations"`
+	OperationIds types.Map    `tfsdk:"operation_ids"`
+	Group        types.String `tfsdk:"group"`
+	Id           types.String `tfsdk:"id"`
+}
+
+// spacePermissionResourceModelV1 describes the data model of schema version 1
+type spacePermissionResourceModelV1 struct {
+	Key          types.String `tfsdk:"key"`
+	Operations   types.List   `tfsdk:"operations"`
+	OperationIds types.Map    `tfsdk:"operation_ids"`
+	Group        types.String `tfsdk:"group"`
+	User         types.String `tfsdk:"user"`
+	Anonymous    types.Bool   `tfsdk:"anonymous"`
+	Id           types.String `tfsdk:"id"`
+}
+
+// upgradeSpacePermissionStateV0 upgrades the group permissions of version 0, users and anonymous
+// visitors did not exist yet
+func upgradeSpacePermissionStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior spacePermissionResourceModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	setUpgradedSpacePermissionState(ctx, resp, spacePermissionResourceModelV1{
+		Key:          prior.Key,
+		Operations:   prior.Operations,
+		OperationIds: prior.OperationIds,
+		Group:        prior.Group,
+		User:         types.StringNull(),
+		Anonymous:    types.BoolNull(),
+		Id:           prior.Id,
+	})
+}
+
+func upgradeSpacePermissionStateV1(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior spacePermissionResourceModelV1
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	setUpgradedSpacePermissionState(ctx, resp, prior)
+}
+
+// setUpgradedSpacePermissionState converts the operations into a set and rewrites the id. The operation
+// ids are kept, they are already keyed by operation:target.
+func setUpgradedSpacePermissionState(ctx context.Context, resp *resource.UpgradeStateResponse, prior spacePermissionResourceModelV1) {
+	var operations []string
+	resp.Diagnostics.Append(prior.Operations.ElementsAs(ctx, &operations, false)...)
+	data := SpacePermissionResourceModel{
+		Key:          prior.Key,
+		OperationIds: prior.OperationIds,
+		Group:        prior.Group,
+		User:         prior.User,
+		Anonymous:    prior.Anonymous,
+	}
+	var diags diag.Diagnostics
+	data.Operations, diags = types.SetValueFrom(ctx, types.StringType, operations)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Id = types.StringValue(spacePermissionId(&data))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// spacePermissionId identifies the permissions by the space and the subject they are granted to
+func spacePermissionId(data *SpacePermissionResourceModel) string {
+	key := spacekey.Normalize(data.Key.ValueString())
+	subject := subjectFromResourceModel(data)
+	if subject.Type == "anonymous" {
+		return key + "/anonymous"
+	}
+	return fmt.Sprintf("%s/%s/%s", key, subject.Type, subject.Identifier)
+}
+
+// parseSpacePermissionId splits an identifier built by spacePermissionId, group names may contain slashes
+func parseSpacePermissionId(id string) (string, permissionSubject, bool) {
+	parts := strings.SplitN(id, "/", 3)
+	if spacekey.Validate(parts[0]) != nil {
+		return "", permissionSubject{}, false
+	}
+	switch {
+	case len(parts) == 2 && parts[1] == "anonymous":
+		return parts[0], permissionSubject{Type: "anonymous"}, true
+	case len(parts) == 3 && (parts[1] == "group" || parts[1] == "user") && parts[2] != "":
+		return parts[0], permissionSubject{Type: parts[1], Identifier: parts[2]}, true
+	}
+	return "", permissionSubject{}, false
 }
 
 func spacePermissionMappingFromResourceModel(ctx context.Context, data *SpacePermissionResourceModel) []*transferobjects.SpacePermission {
-	var collection []*transferobjects.SpacePermission
 	var permissions []string
 
 	data.Operations.ElementsAs(ctx, &permissions, false)
 
-	if helpers.Contains(permissions, "read:space") && len(permissions) > 1 && permissions[0] != "read:space" {
-		permissions = helpers.MoveToFirstPositionOfSlice(permissions, "read:space")
+	return spacePermissionMappingFromOperations(subjectFromResourceModel(data), permissions)
+}
+
+// subjectFromResourceModel returns the configured subject of the permissions
+func subjectFromResourceModel(data *SpacePermissionResourceModel) permissionSubject {
+	if !data.User.IsNull() {
+		return permissionSubject{Type: "user", Identifier: data.User.ValueString()}
+	}
+	if data.Anonymous.ValueBool() {
+		return permissionSubject{Type: "anonymous"}
 	}
+	return permissionSubject{Type: "group", Identifier: data.Group.ValueString()}
+}
+
+// spacePermissionMappingFromOperations builds the permission requests for a subject in alphabetical order,
+// granting read:space first
+func spacePermissionMappingFromOperations(subject permissionSubject, operations []string) []*transferobjects.SpacePermission {
+	var collection []*transferobjects.SpacePermission
+
+	permissions := append([]string{}, operations...)
+	sort.Strings(permissions)
+	permissions = helpers.MoveToFirstPositionOfSlice(permissions, "read:space")
 	for _, permission := range permissions {
 		permissionParts := strings.Split(permission, ":")
-		subject := &transferobjects.Subject{
-			Type:       "group",
-			Identifier: data.Group.ValueString(),
-		}
 		operation := &transferobjects.Operation{
 			Key:    permissionParts[0],
 			Target: permissionParts[1],
 		}
 		spacePermission := &transferobjects.SpacePermission{
 			Id:        0,
-			Subject:   subject,
 			Operation: operation,
 		}
+		if subject.Type == "anonymous" {
+			spacePermission.AnonymousAccess = true
+		} else {
+			spacePermission.Subject = &transferobjects.Subject{
+				Type:       subject.Type,
+				Identifier: subject.Identifier,
+			}
+		}
 		collection = append(collection, spacePermission)
 	}
 	return collection
 }
-func generateIdFromSummaryResponse(ctx context.Context, data *SpacePermissionResourceModel, spacePermissions *transferobjects.SummarySpacePermissions) (string, map[string]attr.Value) {
-	var permissionIds []string
-	var elements = make(map[string]attr.Value)
+
+// revocationOrder sorts the operations alphabetically. read:space is the prerequisite of every other
+// operation, so it is revoked last.
+func revocationOrder(operations []string) []string {
+	ordered := append([]string{}, operations...)
+	sort.Strings(ordered)
+	return helpers.MoveToLastPositionOfSlice(ordered, "read:space")
+}
+
+// operationIdsFromSummaryResponse returns the permission ids of the subject keyed by operation:target.
+// It also lists the operations whose subjects were truncated by Confluence, the subject may be hidden
+// in those.
+func operationIdsFromSummaryResponse(ctx context.Context, data *SpacePermissionResourceModel, spacePermissions *transferobjects.SummarySpacePermissions) (map[string]string, []string) {
+	var truncated []string
+	var elements = make(map[string]string)
+	subject := subjectFromResourceModel(data)
 	for _, permission := range spacePermissions.Permissions {
-		if permission.Subjects.Group != nil && len(permission.Subjects.Group.Results) > 0 {
-			for _, group := range permission.Subjects.Group.Results {
-				if data.Group.ValueString() == group.Name {
-					permissionIds = append(permissionIds, strconv.Itoa(permission.ID))
-					key := fmt.Sprintf("%s:%s", permission.Operation.Operation, permission.Operation.TargetType)
-					elements[key] = types.StringValue(strconv.Itoa(permission.ID))
-				}
+		key := fmt.Sprintf("%s:%s", permission.Operation.Operation, permission.Operation.TargetType)
+		if subject.matches(&permission) {
+			elements[key] = strconv.Itoa(permission.ID)
+		}
+		if subject.truncatedIn(&permission) {
+			truncated = append(truncated, key)
+		}
+	}
+	return elements, truncated
+}
+
+// subjectOperationIds pages through the individual permissions of the space and returns the permission ids
+// of the subject for the given operations, keyed by operation:target
+func subjectOperationIds(ctx context.Context, client *helpers.Client, key string, subject permissionSubject, operations []string) (map[string]string, error) {
+	elements := make(map[string]string)
+	permissions := helpers.Paginate[transferobjects.SpacePermission](client, fmt.Sprintf("/rest/api/space/%s/permission", spacekey.Normalize(key)))
+	for permissions.Next(ctx) {
+		permission := permissions.Value()
+		if permission.Operation == nil || permission.Subject == nil {
+			continue
+		}
+		operation := fmt.Sprintf("%s:%s", permission.Operation.Key, permission.Operation.Target)
+		if helpers.Contains(operations, operation) && permission.Subject.Type == subject.Type && permission.Subject.Identifier == subject.Identifier {
+			elements[operation] = permission.Id.String()
+		}
+	}
+	if err := permissions.Err(); err != nil {
+		return nil, err
+	}
+	return elements, nil
+}
+
+// matches reports whether a permission returned by Confluence is granted to the subject
+func (s permissionSubject) matches(permission *transferobjects.SavedPermission) bool {
+	subjects := permission.Subjects
+	switch s.Type {
+	case "anonymous":
+		return permission.AnonymousAccess &&
+			(subjects.Group == nil || len(subjects.Group.Results) == 0) &&
+			(subjects.User == nil || len(subjects.User.Results) == 0)
+	case "user":
+		if subjects.User == nil {
+			return false
+		}
+		for _, user := range subjects.User.Results {
+			if s.Identifier == user.AccountID || s.Identifier == user.UserKey {
+				return true
 			}
 		}
+	case "group":
+		if subjects.Group == nil {
+			return false
+		}
+		for _, group := range subjects.Group.Results {
+			if s.Identifier == group.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// truncatedIn reports whether Confluence returned only part of the subjects of the subject's type
+func (s permissionSubject) truncatedIn(permission *transferobjects.SavedPermission) bool {
+	subjects := permission.Subjects
+	switch s.Type {
+	case "user":
+		return subjects.User != nil && subjects.User.Size > len(subjects.User.Results)
+	case "group":
+		return subjects.Group != nil && subjects.Group.Size > len(subjects.Group.Results)
 	}
-	sort.Strings(permissionIds)
-	return strings.Join(permissionIds[:], ":"), elements
+	return false
 }
@@ -1,12 +1,22 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"github.com/fatih/structs"
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"reflect"
 	"strings"
 	"terraform-provider-confluence/internal/fakeserver"
+	"terraform-provider-confluence/internal/helpers"
 	"terraform-provider-confluence/internal/provider/transferobjects"
 	"testing"
 )
@@ -14,7 +24,7 @@ import (
 func generateTestSpacePermission() (string, string, []string) {
 	key := "KEY"
 	group := "groupName"
-	permissions := []string{"create:page", "create:blogpost", "create:comment", "create:attachment"}
+	permissions := []string{"read:space", "create:page", "create:blogpost", "create:comment", "create:attachment"}
 	return key, group, permissions
 }
 
@@ -148,3 +158,309 @@ func testAccGenerateSpacePermissionObjects(key string, group string, permissions
 	}
 	return summary
 }
+
+func TestSpacePermissionMappingFromOperations(t *testing.T) {
+	requests := spacePermissionMappingFromOperations(permissionSubject{Type: "group", Identifier: "groupName"}, []string{"create:page", "read:space", "delete:page"})
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 permission requests, got %d", len(requests))
+	}
+	if requests[0].Operation.Key != "read" || requests[0].Operation.Target != "space" {
+		t.Fatalf("expected read:space to be granted first, got %s:%s", requests[0].Operation.Key, requests[0].Operation.Target)
+	}
+	for _, request := range requests {
+		if request.Subject.Type != "group" || request.Subject.Identifier != "groupName" {
+			t.Fatalf("unexpected subject: %+v", request.Subject)
+		}
+	}
+}
+
+func TestRevocationOrder(t *testing.T) {
+	operations := []string{"read:space", "delete:page", "create:page"}
+	if actual := revocationOrder(operations); !reflect.DeepEqual(actual, []string{"create:page", "delete:page", "read:space"}) {
+		t.Fatalf("expected read:space to be revoked last, got %v", actual)
+	}
+	if operations[0] != "read:space" {
+		t.Fatalf("expected the operations to be left unchanged, got %v", operations)
+	}
+}
+
+func TestOperationIdsFromSummaryResponse(t *testing.T) {
+	summary := testAccGenerateSpacePermissionObjects("KEY", "groupName", []string{"read:space", "create:page"})
+	summary.Permissions = append(summary.Permissions, testAccGenerateSpacePermissionObjects("KEY", "otherGroup", []string{"delete:page"}).Permissions...)
+	summary.Permissions[2].Subjects.Group.Size = 5
+
+	data := &SpacePermissionResourceModel{Group: types.StringValue("groupName"), User: types.StringNull(), Anonymous: types.BoolNull()}
+	operationIds, truncated := operationIdsFromSummaryResponse(context.Background(), data, &summary)
+	if len(operationIds) != 2 || operationIds["read:space"] == "" || operationIds["create:page"] == "" {
+		t.Fatalf("expected the operations of the group only, got %v", operationIds)
+	}
+	if len(truncated) != 1 || truncated[0] != "delete:page" {
+		t.Fatalf("expected delete:page to be reported as truncated, got %v", truncated)
+	}
+}
+
+func TestSetGrantedOperations(t *testing.T) {
+	ctx := context.Background()
+	previous, _ := types.SetValueFrom(ctx, types.StringType, []string{"read:space", "delete:page", "create:page"})
+	data := &SpacePermissionResourceModel{Key: types.StringValue("key"), Operations: previous, Group: types.StringValue("groupName"), User: types.StringNull(), Anonymous: types.BoolNull()}
+
+	setGrantedOperations(ctx, data, map[string]string{
+		"create:page":     "3",
+		"read:space":      "1",
+		"create:blogpost": "4",
+	})
+
+	expected, _ := types.SetValueFrom(ctx, types.StringType, []string{"create:blogpost", "create:page", "read:space"})
+	if !data.Operations.Equal(expected) {
+		t.Fatalf("expected %v, got %v", expected, data.Operations)
+	}
+	if data.Id.ValueString() != "KEY/group/groupName" {
+		t.Fatalf("unexpected id %s", data.Id.ValueString())
+	}
+}
+
+func TestSpacePermissionId(t *testing.T) {
+	tests := []struct {
+		data    *SpacePermissionResourceModel
+		id      string
+		subject permissionSubject
+	}{
+		{
+			data:    &SpacePermissionResourceModel{Key: types.StringValue("docs"), Group: types.StringValue("team/docs"), User: types.StringNull(), Anonymous: types.BoolNull()},
+			id:      "DOCS/group/team/docs",
+			subject: permissionSubject{Type: "group", Identifier: "team/docs"},
+		},
+		{
+			data:    &SpacePermissionResourceModel{Key: types.StringValue("~account"), Group: types.StringNull(), User: types.StringValue("5b10a2844c20165700ede21g"), Anonymous: types.BoolNull()},
+			id:      "~account/user/5b10a2844c20165700ede21g",
+			subject: permissionSubject{Type: "user", Identifier: "5b10a2844c20165700ede21g"},
+		},
+		{
+			data:    &SpacePermissionResourceModel{Key: types.StringValue("DOCS"), Group: types.StringNull(), User: types.StringNull(), Anonymous: types.BoolValue(true)},
+			id:      "DOCS/anonymous",
+			subject: permissionSubject{Type: "anonymous"},
+		},
+	}
+	for _, test := range tests {
+		if id := spacePermissionId(test.data); id != test.id {
+			t.Fatalf("expected %s, got %s", test.id, id)
+		}
+		key, subject, ok := parseSpacePermissionId(test.id)
+		if !ok || !strings.EqualFold(key, test.data.Key.ValueString()) || subject != test.subject {
+			t.Fatalf("unexpected parse result for %s: %s %+v %t", test.id, key, subject, ok)
+		}
+	}
+
+	for _, id := range []string{"", "DOCS", "DOCS/group", "DOCS/group/", "DOCS/role/admins", "DOCS/anonymous/user", "DO-CS/anonymous", "1:3:4"} {
+		if _, _, ok := parseSpacePermissionId(id); ok {
+			t.Fatalf("expected %q to be rejected", id)
+		}
+	}
+}
+
+func TestSpacePermissionUpgradeState(t *testing.T) {
+	ctx := context.Background()
+	r := &SpacePermissionResource{}
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	operations, _ := types.ListValueFrom(ctx, types.StringType, []string{"read:space", "create:page"})
+	operationIds, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"read:space": "1", "create:page": "2"})
+	expectedOperations, _ := types.SetValueFrom(ctx, types.StringType, []string{"create:page", "read:space"})
+	priorStates := map[int64]interface{}{
+		0: &spacePermissionResourceModelV0{
+			Key:          types.StringValue("docs"),
+			Operations:   operations,
+			OperationIds: operationIds,
+			Group:        types.StringValue("groupName"),
+			Id:           types.StringValue("1:2"),
+		},
+		1: &spacePermissionResourceModelV1{
+			Key:          types.StringValue("docs"),
+			Operations:   operations,
+			OperationIds: operationIds,
+			Group:        types.StringValue("groupName"),
+			User:         types.StringNull(),
+			Anonymous:    types.BoolNull(),
+			Id:           types.StringValue("DOCS/group/groupName"),
+		},
+	}
+
+	for version, upgrader := range r.UpgradeState(ctx) {
+		prior := tfsdk.State{Schema: *upgrader.PriorSchema, Raw: tftypes.NewValue(upgrader.PriorSchema.Type().TerraformType(ctx), nil)}
+		diags := prior.Set(ctx, priorStates[version])
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		resp := &fwresource.UpgradeStateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}}
+		upgrader.StateUpgrader(ctx, fwresource.UpgradeStateRequest{State: &prior}, resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatal(resp.Diagnostics)
+		}
+
+		var upgraded SpacePermissionResourceModel
+		resp.State.Get(ctx, &upgraded)
+		if upgraded.Id.ValueString() != "DOCS/group/groupName" || !upgraded.Operations.Equal(expectedOperations) || !upgraded.OperationIds.Equal(operationIds) ||
+			!upgraded.User.IsNull() || !upgraded.Anonymous.IsNull() {
+			t.Fatalf("unexpected state upgraded from version %d: %+v", version, upgraded)
+		}
+	}
+}
+
+func TestPermissionSubjectMatches(t *testing.T) {
+	userPermission := transferobjects.SavedPermission{
+		ID: 1,
+		Subjects: transferobjects.SavedPermissionSubjects{
+			User: &transferobjects.SavedPermissionUser{
+				Results: []transferobjects.SavedPermissionUserResult{{AccountID: "account-1", UserKey: "key-1"}},
+				Size:    1,
+			},
+		},
+	}
+	anonymousPermission := transferobjects.SavedPermission{ID: 2, AnonymousAccess: true}
+
+	user := permissionSubject{Type: "user", Identifier: "account-1"}
+	if !user.matches(&userPermission) || user.matches(&anonymousPermission) {
+		t.Fatalf("expected the user subject to match on account id only")
+	}
+	if !(permissionSubject{Type: "user", Identifier: "key-1"}).matches(&userPermission) {
+		t.Fatalf("expected the user subject to match on user key")
+	}
+	anonymous := permissionSubject{Type: "anonymous"}
+	if !anonymous.matches(&anonymousPermission) || anonymous.matches(&userPermission) {
+		t.Fatalf("expected the anonymous subject to match anonymous permissions only")
+	}
+	group := permissionSubject{Type: "group", Identifier: "account-1"}
+	if group.matches(&userPermission) {
+		t.Fatalf("expected a group subject not to match a user permission")
+	}
+
+	requests := spacePermissionMappingFromOperations(anonymous, []string{"read:space"})
+	if requests[0].Subject != nil || !requests[0].AnonymousAccess {
+		t.Fatalf("expected an anonymous permission request, got %+v", requests[0])
+	}
+}
+
+func TestSpacePermissionDeleteRevokesV1Ids(t *testing.T) {
+	ctx := context.Background()
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.RequestURI() == "/wiki/rest/api/space/DOCS?expand=permissions":
+			_, _ = w.Write([]byte(`{"id": 98304, "key": "DOCS", "permissions": [
+				{"id": 11, "subjects": {"group": {"results": [{"type": "group", "name": "groupName"}], "size": 1}}, "operation": {"operation": "read", "targetType": "space"}},
+				{"id": 12, "subjects": {"group": {"results": [{"type": "group", "name": "groupName"}], "size": 1}}, "operation": {"operation": "create", "targetType": "page"}}]}`))
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.RequestURI())
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL, _ := url.Parse(server.URL)
+	client := helpers.NewClient(&helpers.NewClientInput{Site: serverURL.Host, PublicSite: serverURL.Host, Context: "/wiki", APIVersion: helpers.APIVersionV2})
+
+	r := &SpacePermissionResource{client: client}
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+	// The state holds the ids read through v2
+	operations, _ := types.SetValueFrom(ctx, types.StringType, []string{"read:space", "create:page"})
+	operationIds, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"read:space": "1", "create:page": "2"})
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	state.Set(ctx, &SpacePermissionResourceModel{
+		Key:          types.StringValue("DOCS"),
+		Operations:   operations,
+		OperationIds: operationIds,
+		Group:        types.StringValue("groupName"),
+		User:         types.StringNull(),
+		Anonymous:    types.BoolNull(),
+		Id:           types.StringValue("DOCS/group/groupName"),
+	})
+
+	resp := &fwresource.DeleteResponse{State: state}
+	r.Delete(ctx, fwresource.DeleteRequest{State: state}, resp)
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() > 0 {
+		t.Fatal(resp.Diagnostics)
+	}
+	if expected := []string{"/wiki/rest/api/space/DOCS/permission/12", "/wiki/rest/api/space/DOCS/permission/11"}; !reflect.DeepEqual(deleted, expected) {
+		t.Fatalf("expected the v1 ids to be revoked with read:space last, got %v", deleted)
+	}
+}
+
+func TestSpacePermissionReadTruncatedSubjects(t *testing.T) {
+	ctx := context.Background()
+	subjects := func(names ...string) string {
+		var results []string
+		for _, name := range names {
+			results = append(results, fmt.Sprintf(`{"type": "group", "name": %q}`, name))
+		}
+		return fmt.Sprintf(`{"group": {"results": [%s], "size": 300}}`, strings.Join(results, ","))
+	}
+	summary := fmt.Sprintf(`{"id": 98304, "key": "DOCS", "permissions": [
+		{"id": 1, "subjects": {"group": {"results": [{"type": "group", "name": "groupName"}], "size": 1}}, "operation": {"operation": "read", "targetType": "space"}},
+		{"id": 2, "subjects": %s, "operation": {"operation": "create", "targetType": "page"}},
+		{"id": 3, "subjects": %s, "operation": {"operation": "delete", "targetType": "page"}}]}`, subjects("other"), subjects("other"))
+	permissions := `{"results": [
+		{"id": 1, "subject": {"type": "group", "identifier": "groupName"}, "operation": {"key": "read", "target": "space"}},
+		{"id": 2, "subject": {"type": "group", "identifier": "other"}, "operation": {"key": "create", "target": "page"}},
+		{"id": 3, "subject": {"type": "group", "identifier": "groupName"}, "operation": {"key": "delete", "target": "page"}}],
+		"start": 0, "limit": 200, "size": 3}`
+
+	for name, test := range map[string]struct {
+		listAvailable bool
+		expected      map[string]string
+	}{
+		"subjects are looked up":        {true, map[string]string{"read:space": "1", "delete:page": "3"}},
+		"lookup fails, operations kept": {false, map[string]string{"read:space": "1", "create:page": "2", "delete:page": "3"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.RequestURI() == "/wiki/rest/api/space/DOCS?expand=permissions":
+					_, _ = w.Write([]byte(summary))
+				case r.URL.Path == "/wiki/rest/api/space/DOCS/permission" && test.listAvailable:
+					_, _ = w.Write([]byte(permissions))
+				default:
+					http.Error(w, "forbidden", http.StatusForbidden)
+				}
+			}))
+			defer server.Close()
+			serverURL, _ := url.Parse(server.URL)
+			client := helpers.NewClient(&helpers.NewClientInput{Site: serverURL.Host, PublicSite: serverURL.Host, Context: "/wiki", APIVersion: helpers.APIVersionV1})
+
+			r := &SpacePermissionResource{client: client}
+			var schemaResp fwresource.SchemaResponse
+			r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+
+			operations, _ := types.SetValueFrom(ctx, types.StringType, []string{"read:space", "create:page", "delete:page"})
+			operationIds, _ := types.MapValueFrom(ctx, types.StringType, map[string]string{"read:space": "1", "create:page": "2", "delete:page": "3"})
+			state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+			state.Set(ctx, &SpacePermissionResourceModel{
+				Key:          types.StringValue("DOCS"),
+				Operations:   operations,
+				OperationIds: operationIds,
+				Group:        types.StringValue("groupName"),
+				User:         types.StringNull(),
+				Anonymous:    types.BoolNull(),
+				Id:           types.StringValue("DOCS/group/groupName"),
+			})
+
+			resp := &fwresource.ReadResponse{State: state}
+			r.Read(ctx, fwresource.ReadRequest{State: state}, resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+			var data SpacePermissionResourceModel
+			resp.State.Get(ctx, &data)
+			var actual map[string]string
+			data.OperationIds.ElementsAs(ctx, &actual, false)
+			if !reflect.DeepEqual(actual, test.expected) {
+				t.Fatalf("expected operation ids %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
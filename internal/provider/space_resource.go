@@ -3,13 +3,17 @@ package provider
 import (
 	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"terraform-provider-confluence/internal/helpers"
+	"terraform-provider-confluence/internal/provider/spacekey"
 	"terraform-provider-confluence/internal/provider/transferobjects"
 )
 
@@ -28,10 +32,15 @@ type SpaceResource struct {
 
 // SpaceResourceModel describes the resource data model.
 type SpaceResourceModel struct {
-	Key  types.String `tfsdk:"key"`
-	Name types.String `tfsdk:"name"`
-	Url  types.String `tfsdk:"url"`
-	Id   types.String `tfsdk:"id"`
+	Key             types.String `tfsdk:"key"`
+	Name            types.String `tfsdk:"name"`
+	Description     types.String `tfsdk:"description"`
+	DescriptionView types.String `tfsdk:"description_view"`
+	Homepage        types.String `tfsdk:"homepage"`
+	Type            types.String `tfsdk:"type"`
+	Status          types.String `tfsdk:"status"`
+	Url             types.String `tfsdk:"url"`
+	Id              types.String `tfsdk:"id"`
 }
 
 func (r *SpaceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -47,14 +56,73 @@ func (r *SpaceResource) Schema(ctx context.Context, req resource.SchemaRequest,
 			"key": schema.StringAttribute{
 				MarkdownDescription: "The space key of the confluence space (all caps)",
 				Required:            true,
+				Validators: []validator.String{
+					spacekey.Validator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					spacekey.RequiresReplace(),
+				},
 			},
 			"name": schema.StringAttribute{
-				MarkdownDescription: "The name of the confluence space",
+				MarkdownDescription: "The name of the confluence space (defaults to the space key)",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "The plain text description of the space. Keeps the description set in Confluence when not configured, set it to an empty string to clear it",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"description_view": schema.StringAttribute{
+				MarkdownDescription: "The description of the space as rendered by Confluence",
+				Computed:            true,
+			},
+			"homepage": schema.StringAttribute{
+				MarkdownDescription: "The id of the homepage of the space. Keeps the homepage Confluence created with the space when not configured",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"type": schema.StringAttribute{
+				MarkdownDescription: "The type of the space, either `global` or `personal`. Confluence creates global spaces when not configured, changing it replaces the space",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("global", "personal"),
+				},
+				PlanModifiers: []planmodifier.String{
+					// Spaces cannot be converted between global and personal, an unconfigured type keeps the one in state
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The status of the space, either `current` or `archived` (defaults to `current`)",
 				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("current", "archived"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringDefault("current"),
+				},
 			},
 			"url": schema.StringAttribute{
-				MarkdownDescription: "The URL for the space",
+				MarkdownDescription: "The URL of the space",
 				Optional:            true,
+				Computed:            true,
+				DeprecationMessage:  "The URL is computed from the space, remove url from the configuration. A configured value is stored as is.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"id": schema.StringAttribute{
 				Computed:            true,
@@ -89,7 +157,6 @@ func (r *SpaceResource) Configure(ctx context.Context, req resource.ConfigureReq
 
 func (r *SpaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var data *SpaceResourceModel
-	var body transferobjects.Space
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -98,19 +165,33 @@ func (r *SpaceResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	body.Key = data.Key.ValueString()
-	body.Name = data.Name.ValueString()
+	// Confluence creates a homepage for every new space, so it can only be swapped afterwards
+	body := spaceFromResourceModel(data)
+	body.Homepage = nil
 
-	// Create the rule through API
+	// Create the space through API
 	var response transferobjects.Space
-	if err := r.client.Post("/rest/api/space", body, &response, []string{}); err != nil {
+	if err := r.client.Post(ctx, "/rest/api/space", body, &response, []string{}); err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
 		return
 	}
 
+	if !data.Homepage.IsUnknown() || data.Status.ValueString() != "current" {
+		path := fmt.Sprintf("/rest/api/space/%s", spacekey.Normalize(data.Key.ValueString()))
+		if err := r.client.Put(ctx, path, spaceFromResourceModel(data), &response, []string{}); err != nil {
+			resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
+			return
+		}
+	}
+
 	// Save id into the Terraform state.
 	data.Id = types.StringValue(response.Id.String())
 
+	if err := r.refresh(ctx, data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -125,10 +206,13 @@ func (r *SpaceResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// Get the rule through the API
-	var response transferobjects.Space
-	path := fmt.Sprintf("/rest/api/space/%s", data.Key.ValueString())
-	if err := r.client.Get(path, &response); err != nil {
+	// Get the space through the API
+	if err := r.refresh(ctx, data); err != nil {
+		if helpers.IsNotFound(err) {
+			tflog.Warn(ctx, fmt.Sprintf("Space [%s] no longer exists, removing it from state", data.Key.ValueString()))
+			resp.State.RemoveResource(ctx)
+			return
+		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
 		return
 	}
@@ -139,7 +223,6 @@ func (r *SpaceResource) Read(ctx context.Context, req resource.ReadRequest, resp
 
 func (r *SpaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data *SpaceResourceModel
-	var body *transferobjects.Space
 
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -148,14 +231,19 @@ func (r *SpaceResource) Update(ctx context.Context, req resource.UpdateRequest,
 		return
 	}
 
-	// Create the rule through API
+	// Update the space through API
 	var response transferobjects.Space
-	path := fmt.Sprintf("/rest/api/space/%s", data.Key.ValueString())
-	if err := r.client.Put(path, body, &response, []string{}); err != nil {
+	path := fmt.Sprintf("/rest/api/space/%s", spacekey.Normalize(data.Key.ValueString()))
+	if err := r.client.Put(ctx, path, spaceFromResourceModel(data), &response, []string{}); err != nil {
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: \n%s", err))
 		return
 	}
 
+	if err := r.refresh(ctx, data); err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -171,13 +259,91 @@ func (r *SpaceResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 
 	// Get the rule through the API
-	path := fmt.Sprintf("/rest/api/space/%s", data.Key.ValueString())
-	if err := r.client.Delete(path); err != nil {
+	path := fmt.Sprintf("/rest/api/space/%s", spacekey.Normalize(data.Key.ValueString()))
+	if err := r.client.Delete(ctx, path); err != nil {
+		if helpers.IsNotFound(err) {
+			return
+		}
 		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
 		return
 	}
 }
 
 func (r *SpaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("key"), req, resp)
+}
+
+// refresh loads the space from Confluence into the model
+func (r *SpaceResource) refresh(ctx context.Context, data *SpaceResourceModel) error {
+	response, err := newSpaceAPI(r.client).getSpace(ctx, data.Key.ValueString())
+	if err != nil {
+		return err
+	}
+	setSpaceAttributes(r.client, data, response)
+	return nil
+}
+
+// setSpaceAttributes copies a space returned by Confluence into the model
+func setSpaceAttributes(client *helpers.Client, data *SpaceResourceModel, response *transferobjects.Space) {
+	data.Id = types.StringValue(response.Id.String())
+	data.Key = spacekey.Refresh(data.Key, response.Key)
+	data.Name = types.StringValue(response.Name)
+	data.Type = types.StringValue(response.Type)
+	data.Status = types.StringValue(response.Status)
+
+	// An empty description that was configured to clear the description is kept as it is
+	if data.Description.IsUnknown() || data.Description.ValueString() != "" {
+		data.Description = types.StringNull()
+	}
+	data.DescriptionView = types.StringNull()
+	if response.Description != nil {
+		if response.Description.Plain != nil && response.Description.Plain.Value != "" {
+			data.Description = types.StringValue(response.Description.Plain.Value)
+		}
+		if response.Description.View != nil && response.Description.View.Value != "" {
+			data.DescriptionView = types.StringValue(response.Description.View.Value)
+		}
+	}
+
+	if response.Homepage != nil && response.Homepage.Id != "" {
+		data.Homepage = types.StringValue(response.Homepage.Id)
+	} else {
+		data.Homepage = types.StringNull()
+	}
+
+	// url used to be configurable, a configured value is kept until it is removed from the configuration
+	if !data.Url.IsNull() && !data.Url.IsUnknown() {
+		return
+	}
+	if response.Links != nil && response.Links.WebUI != "" {
+		data.Url = types.StringValue(client.URL(response.Links.Context + response.Links.WebUI))
+	} else {
+		data.Url = types.StringNull()
+	}
+}
+
+// spaceFromResourceModel builds the request body for creating or updating a space
+func spaceFromResourceModel(data *SpaceResourceModel) *transferobjects.Space {
+	space := &transferobjects.Space{
+		Key:    spacekey.Normalize(data.Key.ValueString()),
+		Name:   data.Name.ValueString(),
+		Type:   data.Type.ValueString(),
+		Status: data.Status.ValueString(),
+	}
+	// The description is only sent when it is known, an empty plain description clears it
+	if !data.Description.IsUnknown() && !data.Description.IsNull() {
+		space.Description = &transferobjects.SpaceDescription{
+			Plain: &transferobjects.Storage{
+				Value:          data.Description.ValueString(),
+				Representation: "plain",
+			},
+		}
+	}
+	if data.Name.IsUnknown() || data.Name.IsNull() {
+		space.Name = space.Key
+	}
+	if !data.Homepage.IsUnknown() && !data.Homepage.IsNull() {
+		space.Homepage = &transferobjects.SpaceHomepage{Id: data.Homepage.ValueString()}
+	}
+	return space
 }
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 	"terraform-provider-confluence/internal/fakeserver"
+	"terraform-provider-confluence/internal/helpers"
 	"terraform-provider-confluence/internal/provider/transferobjects"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
@@ -74,12 +77,8 @@ func TestSpaceResourceResource(t *testing.T) {
 			{
 				ResourceName:      "confluence_space.test",
 				ImportState:       true,
+				ImportStateId:     generateTestSpaceObject().Key,
 				ImportStateVerify: true,
-				// This is not normally necessary, but is here because this
-				// example code does not have an actual upstream service.
-				// Once the Read method is able to refresh information from
-				// the upstream service, this can be removed.
-				ImportStateVerifyIgnore: []string{"key", "name"},
 			},
 			// Update and Read testing
 			{
@@ -103,3 +102,77 @@ resource "confluence_space" "%s" {
 }
 `, providerConfig, name, space.Key, space.Name)
 }
+
+func TestSpaceFromResourceModel(t *testing.T) {
+	data := &SpaceResourceModel{
+		Key:         types.StringValue("KEY"),
+		Name:        types.StringValue("Runbooks"),
+		Description: types.StringValue("Operational runbooks"),
+		Homepage:    types.StringValue("4242"),
+		Type:        types.StringValue("global"),
+		Status:      types.StringValue("archived"),
+	}
+	space := spaceFromResourceModel(data)
+	if space.Name != "Runbooks" || space.Status != "archived" || space.Type != "global" {
+		t.Fatalf("unexpected space: %+v", space)
+	}
+	if space.Description.Plain.Value != "Operational runbooks" || space.Description.Plain.Representation != "plain" {
+		t.Fatalf("unexpected description: %+v", space.Description.Plain)
+	}
+	if space.Homepage == nil || space.Homepage.Id != "4242" {
+		t.Fatalf("expected the homepage to be sent, got: %+v", space.Homepage)
+	}
+
+	data.Name = types.StringUnknown()
+	data.Description = types.StringNull()
+	data.Homepage = types.StringUnknown()
+	space = spaceFromResourceModel(data)
+	if space.Name != "KEY" {
+		t.Fatalf("expected the name to default to the key, got: %q", space.Name)
+	}
+	if space.Description != nil {
+		t.Fatalf("expected an unconfigured description to be left as it is, got: %+v", space.Description)
+	}
+	if space.Homepage != nil {
+		t.Fatalf("expected no homepage for an unknown value, got: %+v", space.Homepage)
+	}
+
+	data.Description = types.StringValue("")
+	space = spaceFromResourceModel(data)
+	if space.Description == nil || space.Description.Plain.Value != "" {
+		t.Fatalf("expected an empty description to clear it, got: %+v", space.Description)
+	}
+}
+
+func TestSetSpaceAttributes(t *testing.T) {
+	client := helpers.NewClient(&helpers.NewClientInput{Site: "confluence.example.com", PublicSite: "confluence.example.com", Context: "/wiki"})
+	response := &transferobjects.Space{
+		Id:     42,
+		Key:    "KEY",
+		Name:   "Runbooks",
+		Type:   "personal",
+		Status: "current",
+		Links:  &transferobjects.SpaceLinks{Context: "/wiki", WebUI: "/spaces/KEY"},
+	}
+
+	data := &SpaceResourceModel{Key: types.StringValue("key"), Description: types.StringValue(""), Type: types.StringUnknown(), Url: types.StringUnknown()}
+	setSpaceAttributes(client, data, response)
+	if data.Type.ValueString() != "personal" || data.Key.ValueString() != "key" {
+		t.Fatalf("expected the type of the space and the configured key, got: %+v", data)
+	}
+	if data.Description.IsNull() || data.Description.ValueString() != "" {
+		t.Fatalf("expected a configured empty description to be kept, got: %v", data.Description)
+	}
+	if !strings.HasSuffix(data.Url.ValueString(), "confluence.example.com/wiki/spaces/KEY") {
+		t.Fatalf("unexpected url: %v", data.Url)
+	}
+
+	data = &SpaceResourceModel{Key: types.StringValue("KEY"), Description: types.StringNull(), Url: types.StringValue("https://example.com/configured")}
+	setSpaceAttributes(client, data, response)
+	if !data.Description.IsNull() {
+		t.Fatalf("expected no description, got: %v", data.Description)
+	}
+	if data.Url.ValueString() != "https://example.com/configured" {
+		t.Fatalf("expected a configured url to be kept, got: %v", data.Url)
+	}
+}
@@ -0,0 +1,123 @@
+// Package spacekey validates and normalizes Confluence space keys for every attribute that takes one.
+//
+// Confluence stores the keys of global spaces in upper case and treats them case-insensitively, while
+// personal spaces use a `~` followed by the username or account id of their owner.
+package spacekey
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// MaxLength is the longest space key Confluence accepts
+const MaxLength = 255
+
+var (
+	globalKeyPattern   = regexp.MustCompile(`^[A-Za-z0-9]+$`)
+	personalKeyPattern = regexp.MustCompile(`^~[A-Za-z0-9._@:-]+$`)
+)
+
+// IsPersonal reports whether the key belongs to a personal space
+func IsPersonal(key string) bool {
+	return strings.HasPrefix(key, "~")
+}
+
+// Validate returns an error describing why the key is not a valid space key
+func Validate(key string) error {
+	if key == "" {
+		return fmt.Errorf("space key must not be empty")
+	}
+	if len(key) > MaxLength {
+		return fmt.Errorf("space key must be at most %d characters long, got %d", MaxLength, len(key))
+	}
+	if IsPersonal(key) {
+		if !personalKeyPattern.MatchString(key) {
+			return fmt.Errorf("personal space key %q must be a ~ followed by a username or account id", key)
+		}
+		return nil
+	}
+	if !globalKeyPattern.MatchString(key) {
+		return fmt.Errorf("space key %q must only contain the letters A-Z and digits", key)
+	}
+	return nil
+}
+
+// Normalize returns the key the way Confluence stores it. Personal space keys are kept as they are.
+func Normalize(key string) string {
+	if IsPersonal(key) {
+		return key
+	}
+	return strings.ToUpper(key)
+}
+
+// Equal reports whether both keys refer to the same space
+func Equal(a string, b string) bool {
+	return Normalize(a) == Normalize(b)
+}
+
+// Refresh returns the key reported by Confluence, unless it only differs from the current one in case.
+// Keeping the configured spelling prevents a perpetual diff for lower case keys.
+func Refresh(current types.String, remote string) types.String {
+	if !current.IsNull() && !current.IsUnknown() && Equal(current.ValueString(), remote) {
+		return current
+	}
+	return types.StringValue(remote)
+}
+
+// Validator returns a validator for space key attributes
+func Validator() validator.String {
+	return keyValidator{}
+}
+
+type keyValidator struct{}
+
+func (v keyValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("must be a space key of up to %d letters and digits, or a ~ followed by a username for personal spaces", MaxLength)
+}
+
+func (v keyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v keyValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := Validate(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Space Key", err.Error())
+	}
+}
+
+// RequiresReplace returns a plan modifier that replaces the resource when the key refers to another
+// space. Changing only the case of the key is applied in place.
+func RequiresReplace() planmodifier.String {
+	return requiresReplaceModifier{}
+}
+
+type requiresReplaceModifier struct{}
+
+func (m requiresReplaceModifier) Description(_ context.Context) string {
+	return "Changing the space key forces replacement, unless only its case changes."
+}
+
+func (m requiresReplaceModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m requiresReplaceModifier) PlanModifyString(_ context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	// Nothing to replace on create and destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	if req.PlanValue.IsUnknown() || req.StateValue.IsNull() {
+		resp.RequiresReplace = !req.PlanValue.Equal(req.StateValue)
+		return
+	}
+	resp.RequiresReplace = !Equal(req.PlanValue.ValueString(), req.StateValue.ValueString())
+}
@@ -0,0 +1,115 @@
+package spacekey
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestValidate(t *testing.T) {
+	cases := map[string]bool{
+		"KEY":                          true,
+		"key":                          true,
+		"DEV2":                         true,
+		"~jdoe":                        true,
+		"~557058:f1b2c3d4-e5f6":        true,
+		"~john.doe@example.com":        true,
+		"":                             false,
+		"MY KEY":                       false,
+		"MY-KEY":                       false,
+		"~":                            false,
+		"~j doe":                       false,
+		strings.Repeat("A", 255):       true,
+		strings.Repeat("A", 256):       false,
+		"~" + strings.Repeat("a", 300): false,
+	}
+	for key, valid := range cases {
+		if err := Validate(key); (err == nil) != valid {
+			t.Errorf("Validate(%q) = %v, expected valid: %v", key, err, valid)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"key":   "KEY",
+		"Dev2":  "DEV2",
+		"KEY":   "KEY",
+		"~jdoe": "~jdoe",
+	}
+	for key, expected := range cases {
+		if actual := Normalize(key); actual != expected {
+			t.Errorf("Normalize(%q) = %q, expected %q", key, actual, expected)
+		}
+	}
+	if Equal("~jdoe", "~JDOE") {
+		t.Errorf("expected personal space keys to be compared case sensitively")
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	if actual := Refresh(types.StringValue("key"), "KEY"); actual.ValueString() != "key" {
+		t.Errorf("expected the configured spelling to be kept, got %s", actual)
+	}
+	if actual := Refresh(types.StringValue("key"), "OTHER"); actual.ValueString() != "OTHER" {
+		t.Errorf("expected a different key to be refreshed, got %s", actual)
+	}
+	if actual := Refresh(types.StringNull(), "KEY"); actual.ValueString() != "KEY" {
+		t.Errorf("expected an imported key to be set, got %s", actual)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	req := validator.StringRequest{Path: path.Root("key"), ConfigValue: types.StringValue("MY KEY")}
+	resp := &validator.StringResponse{}
+	Validator().ValidateString(context.Background(), req, resp)
+	if resp.Diagnostics.ErrorsCount() != 1 {
+		t.Fatalf("expected an error for an invalid key, got %v", resp.Diagnostics)
+	}
+
+	req.ConfigValue = types.StringUnknown()
+	resp = &validator.StringResponse{}
+	Validator().ValidateString(context.Background(), req, resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("expected unknown keys to be skipped, got %v", resp.Diagnostics)
+	}
+}
+
+func TestRequiresReplace(t *testing.T) {
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"key": tftypes.String}}
+	raw := tftypes.NewValue(objectType, map[string]tftypes.Value{"key": tftypes.NewValue(tftypes.String, "KEY")})
+
+	cases := map[string]struct {
+		state    types.String
+		plan     types.String
+		expected bool
+	}{
+		"unchanged":    {state: types.StringValue("KEY"), plan: types.StringValue("KEY"), expected: false},
+		"case only":    {state: types.StringValue("KEY"), plan: types.StringValue("key"), expected: false},
+		"other space":  {state: types.StringValue("KEY"), plan: types.StringValue("OTHER"), expected: true},
+		"unknown plan": {state: types.StringValue("KEY"), plan: types.StringUnknown(), expected: true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := planmodifier.StringRequest{
+				Path:       path.Root("key"),
+				State:      tfsdk.State{Raw: raw},
+				Plan:       tfsdk.Plan{Raw: raw},
+				StateValue: c.state,
+				PlanValue:  c.plan,
+			}
+			resp := &planmodifier.StringResponse{PlanValue: c.plan}
+			RequiresReplace().PlanModifyString(context.Background(), req, resp)
+			if resp.RequiresReplace != c.expected {
+				t.Errorf("expected RequiresReplace to be %v", c.expected)
+			}
+		})
+	}
+}
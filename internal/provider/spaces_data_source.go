@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"terraform-provider-confluence/internal/helpers"
+	"terraform-provider-confluence/internal/provider/transferobjects"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &SpacesDataSource{}
+
+func NewSpacesDataSource() datasource.DataSource {
+	return &SpacesDataSource{}
+}
+
+// SpacesDataSource defines the data source implementation.
+type SpacesDataSource struct {
+	client *helpers.Client
+}
+
+// SpacesDataSourceModel describes the data source data model.
+type SpacesDataSourceModel struct {
+	Type      types.String                 `tfsdk:"type"`
+	Status    types.String                 `tfsdk:"status"`
+	Label     types.String                 `tfsdk:"label"`
+	Favourite types.Bool                   `tfsdk:"favourite"`
+	Spaces    []SpacesDataSourceSpaceModel `tfsdk:"spaces"`
+	Id        types.String                 `tfsdk:"id"`
+}
+
+// SpacesDataSourceSpaceModel describes a single space of the data source
+type SpacesDataSourceSpaceModel struct {
+	Key         types.String `tfsdk:"key"`
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Homepage    types.String `tfsdk:"homepage"`
+	Type        types.String `tfsdk:"type"`
+	Status      types.String `tfsdk:"status"`
+	Url         types.String `tfsdk:"url"`
+	Id          types.String `tfsdk:"id"`
+}
+
+func (d *SpacesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_spaces"
+}
+
+func (d *SpacesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		// This description is used by the documentation generator and the language server.
+		MarkdownDescription: "Spaces data source, lists the spaces visible to the configured user",
+
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				MarkdownDescription: "Only list spaces of this type, either `global` or `personal`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("global", "personal"),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "Only list spaces with this status, either `current` or `archived`",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("current", "archived"),
+				},
+			},
+			"label": schema.StringAttribute{
+				MarkdownDescription: "Only list spaces with this label",
+				Optional:            true,
+			},
+			"favourite": schema.BoolAttribute{
+				MarkdownDescription: "Set to `true` to only list the spaces the configured user marked as favourite",
+				Optional:            true,
+			},
+			"spaces": schema.ListNestedAttribute{
+				MarkdownDescription: "The matching spaces ordered by key",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							MarkdownDescription: "The space key of the space",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the space",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "The plain text description of the space",
+							Computed:            true,
+						},
+						"homepage": schema.StringAttribute{
+							MarkdownDescription: "The id of the homepage of the space",
+							Computed:            true,
+						},
+						"type": schema.StringAttribute{
+							MarkdownDescription: "The type of the space, either `global` or `personal`",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "The status of the space, either `current` or `archived`",
+							Computed:            true,
+						},
+						"url": schema.StringAttribute{
+							MarkdownDescription: "The URL of the space",
+							Computed:            true,
+						},
+						"id": schema.StringAttribute{
+							MarkdownDescription: "The id of the space",
+							Computed:            true,
+						},
+					},
+				},
+			},
+			"id": schema.StringAttribute{
+				MarkdownDescription: "Data source identifier",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *SpacesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*helpers.Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *SpacesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data SpacesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filter := spaceFilter{
+		Type:      data.Type.ValueString(),
+		Status:    data.Status.ValueString(),
+		Label:     data.Label.ValueString(),
+		Favourite: data.Favourite.ValueBool(),
+	}
+
+	// Get the spaces through the API, all pages are read
+	spaces, err := newSpaceAPI(d.client).listSpaces(ctx, filter)
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Error during request, got error: %s", err))
+		return
+	}
+	data.Spaces = spacesDataSourceSpaces(d.client, spaces)
+
+	// Save id into the Terraform state.
+	data.Id = types.StringValue(helpers.Sha256String(fmt.Sprintf("%+v", filter)))
+
+	// Write logs using the tflog package
+	tflog.Trace(ctx, fmt.Sprintf("read %d spaces", len(data.Spaces)))
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// spacesDataSourceSpaces converts the listed spaces into the model, ordered by key
+func spacesDataSourceSpaces(client *helpers.Client, spaces []transferobjects.Space) []SpacesDataSourceSpaceModel {
+	result := make([]SpacesDataSourceSpaceModel, 0, len(spaces))
+	for _, space := range spaces {
+		item := SpacesDataSourceSpaceModel{
+			Key:         types.StringValue(space.Key),
+			Name:        types.StringValue(space.Name),
+			Description: types.StringNull(),
+			Homepage:    types.StringNull(),
+			Type:        types.StringValue(space.Type),
+			Status:      types.StringValue(space.Status),
+			Url:         types.StringNull(),
+			Id:          types.StringValue(space.Id.String()),
+		}
+		if space.Description != nil && space.Description.Plain != nil && space.Description.Plain.Value != "" {
+			item.Description = types.StringValue(space.Description.Plain.Value)
+		}
+		if space.Homepage != nil && space.Homepage.Id != "" {
+			item.Homepage = types.StringValue(space.Homepage.Id)
+		}
+		if space.Links != nil && space.Links.WebUI != "" {
+			item.Url = types.StringValue(client.URL(space.Links.Context + space.Links.WebUI))
+		}
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key.ValueString() < result[j].Key.ValueString()
+	})
+	return result
+}
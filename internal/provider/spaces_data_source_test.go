@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"terraform-provider-confluence/internal/fakeserver"
+	"terraform-provider-confluence/internal/helpers"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSpacesDataSource(t *testing.T) {
+	debug := true
+	apiServerObjects := make(map[string]map[string]interface{})
+
+	svr := fakeserver.NewFakeServer(testPost, apiServerObjects, false, debug, "")
+	defer svr.Shutdown()
+
+	// The spaces are returned out of order, the data source sorts them by key
+	svr.SetSplice("/rest/api/space", func(a string, b []byte) (string, map[string]interface{}) {
+		var obj map[string]interface{}
+		_ = json.Unmarshal([]byte(`{
+			"results": [
+				{"id": 2, "key": "OPS", "name": "Operations", "type": "global", "status": "current"},
+				{"id": 1, "key": "DOCS", "name": "Docs", "type": "global", "status": "current"}],
+			"start": 0, "limit": 200, "size": 2, "_links": {}}`), &obj)
+		return "spaces", obj
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			svr.StartInBackground()
+		},
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: testAccSpacesDataSourceConfig("test", "global"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.confluence_spaces.test", "spaces.#", "2"),
+					resource.TestCheckResourceAttr("data.confluence_spaces.test", "spaces.0.key", "DOCS"),
+					resource.TestCheckResourceAttr("data.confluence_spaces.test", "spaces.1.key", "OPS"),
+					resource.TestCheckResourceAttr("data.confluence_spaces.test", "id", helpers.Sha256String(fmt.Sprintf("%+v", spaceFilter{Type: "global"}))),
+				),
+			},
+		},
+	})
+}
+
+func testAccSpacesDataSourceConfig(name string, spaceType string) string {
+	return fmt.Sprintf(`%s
+data "confluence_spaces" "%s" {
+	type = "%s"
+}
+`, providerConfig, name, spaceType)
+}
@@ -9,6 +9,7 @@ type Body struct {
 type Content struct {
 	Id        string           `json:"id,omitempty"`
 	Type      string           `json:"type,omitempty"`
+	Status    string           `json:"status,omitempty"`
 	Title     string           `json:"title,omitempty"`
 	Space     *SpaceKey        `json:"space,omitempty"`
 	Version   *Version         `json:"version,omitempty"`
@@ -49,40 +50,5 @@ type ContentMetadata struct {
 type Label struct {
 	Prefix string `json:"prefix,omitempty"`
 	Name   string `json:"name,omitempty"`
+	Id     string `json:"id,omitempty"`
 }
-
-//
-//func (c *Client) CreateContent(content *Content) (*Content, error) {
-//	var response Content
-//	if err := c.Post("/rest/api/content", content, &response); err != nil {
-//		return nil, err
-//	}
-//	return &response, nil
-//}
-//
-//func (c *Client) GetContent(id string) (*Content, error) {
-//	var response Content
-//	path := fmt.Sprintf("/rest/api/content/%s?expand=space,body.storage,version,ancestors", id)
-//	if err := c.Get(path, &response); err != nil {
-//		return nil, err
-//	}
-//	return &response, nil
-//}
-//
-//func (c *Client) UpdateContent(content *Content) (*Content, error) {
-//	var response Content
-//	content.Version.Number++
-//	path := fmt.Sprintf("/rest/api/content/%s", content.Id)
-//	if err := c.Put(path, content, &response); err != nil {
-//		return nil, err
-//	}
-//	return &response, nil
-//}
-//
-//func (c *Client) DeleteContent(id string) error {
-//	path := fmt.Sprintf("/rest/api/content/%s", id)
-//	if err := c.Delete(path); err != nil {
-//		return err
-//	}
-//	return nil
-//}
@@ -10,6 +10,7 @@ type Group struct {
 
 // GenericLinks is part of Content
 type GroupLinks struct {
-	Base  string `json:"base,omitempty"`
-	WebUI string `json:"webui,omitempty"`
+	Base    string `json:"base,omitempty"`
+	Context string `json:"context,omitempty"`
+	WebUI   string `json:"webui,omitempty"`
 }
@@ -1,15 +1,49 @@
 package transferobjects
 
-// Content is a primary resource in Confluence
+// Space is a primary resource in Confluence
 type Space struct {
-	Id    FlexInt     `json:"id,omitempty"`
-	Name  string      `json:"name,omitempty"`
-	Key   string      `json:"key,omitempty"`
-	Links *SpaceLinks `json:"_links,omitempty"`
+	Id          FlexInt           `json:"id,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Key         string            `json:"key,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Status      string            `json:"status,omitempty"`
+	Description *SpaceDescription `json:"description,omitempty"`
+	Homepage    *SpaceHomepage    `json:"homepage,omitempty"`
+	Links       *SpaceLinks       `json:"_links,omitempty"`
 }
 
-// ContentLinks is part of Content
+// SpaceDescription is part of Space
+type SpaceDescription struct {
+	Plain *Storage `json:"plain,omitempty"`
+	View  *Storage `json:"view,omitempty"`
+}
+
+// SpaceHomepage is part of Space
+type SpaceHomepage struct {
+	Id string `json:"id,omitempty"`
+}
+
+// SpaceLinks is part of Space
 type SpaceLinks struct {
-	Base  string `json:"base,omitempty"`
-	WebUI string `json:"webui,omitempty"`
+	Base    string `json:"base,omitempty"`
+	Context string `json:"context,omitempty"`
+	WebUI   string `json:"webui,omitempty"`
+}
+
+// SpaceV2 is a space as returned by the v2 API
+type SpaceV2 struct {
+	Id          FlexInt             `json:"id,omitempty"`
+	Key         string              `json:"key,omitempty"`
+	Name        string              `json:"name,omitempty"`
+	Type        string              `json:"type,omitempty"`
+	Status      string              `json:"status,omitempty"`
+	HomepageId  string              `json:"homepageId,omitempty"`
+	Description *SpaceV2Description `json:"description,omitempty"`
+	Links       *SpaceLinks         `json:"_links,omitempty"`
+}
+
+// SpaceV2Description is part of SpaceV2, it only holds the format requested with description-format
+type SpaceV2Description struct {
+	Plain *Storage `json:"plain,omitempty"`
+	View  *Storage `json:"view,omitempty"`
 }
@@ -2,10 +2,11 @@ package transferobjects
 
 // Content is a primary resource in Confluence
 type SpacePermission struct {
-	Id        FlexInt               `json:"id,omitempty"`
-	Subject   *Subject              `json:"subject,omitempty"`
-	Operation *Operation            `json:"operation,omitempty"`
-	Links     *SpacePermissionLinks `json:"_links,omitempty"`
+	Id              FlexInt               `json:"id,omitempty"`
+	Subject         *Subject              `json:"subject,omitempty"`
+	Operation       *Operation            `json:"operation,omitempty"`
+	AnonymousAccess bool                  `json:"anonymousAccess,omitempty"`
+	Links           *SpacePermissionLinks `json:"_links,omitempty"`
 }
 
 // Subject is part of SpacePermission
@@ -27,6 +28,7 @@ type SpacePermissionLinks struct {
 	Context    string `json:"context,omitempty"`
 	Self       string `json:"self,omitempty"`
 	Collection string `json:"collection,omitempty"`
+	Next       string `json:"next,omitempty"`
 }
 
 // SummarySpacePermissions is the permissions object returned by the GET api call
@@ -52,30 +54,37 @@ type SavedPermission struct {
 }
 
 type SavedPermissionSubjects struct {
-	User struct {
-		Results []struct {
-			Type           string `json:"type,omitempty"`
-			AccountID      string `json:"accountId,omitempty"`
-			AccountType    string `json:"accountType,omitempty"`
-			Email          string `json:"email,omitempty"`
-			PublicName     string `json:"publicName,omitempty"`
-			ProfilePicture struct {
-				Path      string `json:"path,omitempty"`
-				Width     int    `json:"width,omitempty"`
-				Height    int    `json:"height,omitempty"`
-				IsDefault bool   `json:"isDefault,omitempty"`
-			} `json:"profilePicture,omitempty"`
-			DisplayName            string                `json:"displayName,omitempty"`
-			IsExternalCollaborator bool                  `json:"isExternalCollaborator,omitempty"`
-			Expandable             *Expandable           `json:"_expandable,omitempty"`
-			Links                  *SpacePermissionLinks `json:"_links,omitempty"`
-		} `json:"results,omitempty"`
-		Size int `json:"size,omitempty"`
-	} `json:"user,omitempty"`
+	User       *SavedPermissionUser  `json:"user,omitempty"`
 	Group      *SavedPermissionGroup `json:"group,omitempty"`
 	Expandable *Expandable           `json:"_expandable,omitempty"`
 }
 
+// SavedPermissionUser is part of SavedPermissionSubjects
+type SavedPermissionUser struct {
+	Results []SavedPermissionUserResult `json:"results,omitempty"`
+	Size    int                         `json:"size,omitempty"`
+}
+
+type SavedPermissionUserResult struct {
+	Type           string `json:"type,omitempty"`
+	AccountID      string `json:"accountId,omitempty"`
+	UserKey        string `json:"userKey,omitempty"`
+	Username       string `json:"username,omitempty"`
+	AccountType    string `json:"accountType,omitempty"`
+	Email          string `json:"email,omitempty"`
+	PublicName     string `json:"publicName,omitempty"`
+	ProfilePicture struct {
+		Path      string `json:"path,omitempty"`
+		Width     int    `json:"width,omitempty"`
+		Height    int    `json:"height,omitempty"`
+		IsDefault bool   `json:"isDefault,omitempty"`
+	} `json:"profilePicture,omitempty"`
+	DisplayName            string                `json:"displayName,omitempty"`
+	IsExternalCollaborator bool                  `json:"isExternalCollaborator,omitempty"`
+	Expandable             *Expandable           `json:"_expandable,omitempty"`
+	Links                  *SpacePermissionLinks `json:"_links,omitempty"`
+}
+
 type SavedPermissionOperation struct {
 	Operation  string `json:"operation,omitempty"`
 	TargetType string `json:"targetType,omitempty"`
@@ -108,3 +117,23 @@ type Expandable struct {
 	Homepage      string `json:"homepage,omitempty"`
 	User          string `json:"user,omitempty"`
 }
+
+// SpacePermissionV2 is a space permission as returned by the v2 API
+type SpacePermissionV2 struct {
+	Id        FlexInt                    `json:"id,omitempty"`
+	Principal SpacePermissionV2Principal `json:"principal"`
+	Operation SpacePermissionV2Operation `json:"operation"`
+}
+
+// SpacePermissionV2Principal is part of SpacePermissionV2
+type SpacePermissionV2Principal struct {
+	// Type is one of user, group or role
+	Type string `json:"type,omitempty"`
+	Id   string `json:"id,omitempty"`
+}
+
+// SpacePermissionV2Operation is part of SpacePermissionV2
+type SpacePermissionV2Operation struct {
+	Key        string `json:"key,omitempty"`
+	TargetType string `json:"targetType,omitempty"`
+}
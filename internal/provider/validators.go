@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-confluence/internal/helpers"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// spacePermissionOperations returns a validator that checks a set of space permission operations
+// against the operation:target pairs known to Confluence. read:space has to be part of the set as soon
+// as any other operation is granted.
+func spacePermissionOperations() validator.Set {
+	return spacePermissionOperationsValidator{}
+}
+
+type spacePermissionOperationsValidator struct{}
+
+func (v spacePermissionOperationsValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("each operation must be one of %s, and read:space is required with any other operation", strings.Join(validPermissions, ", "))
+}
+
+func (v spacePermissionOperationsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v spacePermissionOperationsValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	var operations []string
+	allKnown := true
+	for _, element := range req.ConfigValue.Elements() {
+		elementPath := req.Path.AtSetValue(element)
+		operation, ok := element.(types.String)
+		if !ok || operation.IsUnknown() {
+			allKnown = false
+			continue
+		}
+		if operation.IsNull() {
+			resp.Diagnostics.AddAttributeError(elementPath, "Invalid Space Permission Operation", "Operations must not be null")
+			continue
+		}
+
+		value := operation.ValueString()
+		if !strings.Contains(value, ":") {
+			resp.Diagnostics.AddAttributeError(
+				elementPath,
+				"Invalid Space Permission Operation",
+				fmt.Sprintf("Operation %q must have the form operation:target, e.g. create:page", value),
+			)
+		} else if !helpers.Contains(validPermissions, value) {
+			resp.Diagnostics.AddAttributeError(
+				elementPath,
+				"Invalid Space Permission Operation",
+				fmt.Sprintf("Operation %q is not supported by Confluence, expected one of: %s", value, strings.Join(validPermissions, ", ")),
+			)
+		}
+		operations = append(operations, value)
+	}
+
+	if !allKnown || len(operations) == 0 {
+		return
+	}
+	if !helpers.Contains(operations, "read:space") {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Missing read:space Operation",
+			"Confluence requires read:space whenever any other operation is granted in a space",
+		)
+	}
+}
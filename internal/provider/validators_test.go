@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestSpacePermissionOperationsValidator(t *testing.T) {
+	cases := map[string]struct {
+		operations []attr.Value
+		errors     int
+		errorPath  path.Path
+	}{
+		"valid": {
+			operations: []attr.Value{types.StringValue("read:space"), types.StringValue("create:page")},
+		},
+		"read space only": {
+			operations: []attr.Value{types.StringValue("read:space")},
+		},
+		"typo": {
+			operations: []attr.Value{types.StringValue("read:space"), types.StringValue("create:pages")},
+			errors:     1,
+			errorPath:  path.Root("operations").AtSetValue(types.StringValue("create:pages")),
+		},
+		"missing colon": {
+			operations: []attr.Value{types.StringValue("read:space"), types.StringValue("administer")},
+			errors:     1,
+			errorPath:  path.Root("operations").AtSetValue(types.StringValue("administer")),
+		},
+		"missing read space": {
+			operations: []attr.Value{types.StringValue("create:page")},
+			errors:     1,
+			errorPath:  path.Root("operations"),
+		},
+		"unknown element": {
+			operations: []attr.Value{types.StringUnknown(), types.StringValue("create:page")},
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			value, diags := types.SetValue(types.StringType, c.operations)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			req := validator.SetRequest{Path: path.Root("operations"), ConfigValue: value}
+			resp := &validator.SetResponse{}
+			spacePermissionOperations().ValidateSet(context.Background(), req, resp)
+
+			if resp.Diagnostics.ErrorsCount() != c.errors {
+				t.Fatalf("expected %d errors, got %v", c.errors, resp.Diagnostics)
+			}
+			if c.errors > 0 {
+				withPath, ok := resp.Diagnostics.Errors()[0].(interface{ Path() path.Path })
+				if !ok || !withPath.Path().Equal(c.errorPath) {
+					t.Fatalf("expected error at %s, got %v", c.errorPath, resp.Diagnostics)
+				}
+			}
+		})
+	}
+}